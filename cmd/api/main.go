@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"time"
@@ -11,11 +12,16 @@ import (
 	// orderModel "main/internal/order/model"
 	// productModel "main/internal/product/model"
 	addressModel "main/internal/address/model"
+	addressRepository "main/internal/address/repository"
 	grpcServer "main/internal/server/grpc"
 	httpServer "main/internal/server/http"
 	userModel "main/internal/user/model"
+	userRepository "main/internal/user/repository"
+	"main/pkg/apikey"
+	"main/pkg/audit"
 	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/jtoken"
 	"main/pkg/redis"
 )
 
@@ -34,6 +40,10 @@ import (
 //	@in							header
 //	@name						Authorization
 
+//	@securityDefinitions.apikey	MachineKeyAuth
+//	@in							header
+//	@name						X-API-Key
+
 // @title User API
 // @description API for user management
 // @version 1.0
@@ -42,6 +52,9 @@ import (
 func main() {
 	cfg := config.LoadConfig()
 	logger.Initialize(cfg.Environment)
+	if err := jtoken.LoadKeys(cfg); err != nil {
+		logger.Fatal("Cannot load token keys", err)
+	}
 	//*********************************************
 
 	// db, err := dbs.NewDatabase(cfg.DatabaseURI)
@@ -66,7 +79,16 @@ func main() {
 	}
 	//*********************************************
 
-	err = db.AutoMigrate(&userModel.User{}, &addressModel.Address{})
+	failures, err := addressRepository.MigrateCoordinates(context.Background(), db)
+	if err != nil {
+		logger.Fatal("Address coordinates migration fail", err)
+	}
+	for _, failure := range failures {
+		logger.Warnf("Cleared unparsable coordinates of address %s (lat %q, long %q): %s",
+			failure.ID, failure.Lat, failure.Long, failure.Reason)
+	}
+
+	err = db.AutoMigrate(&userModel.User{}, &addressModel.Address{}, &apikey.Key{}, &audit.Event{})
 	if err != nil {
 		logger.Fatal("Database migration fail", err)
 	}
@@ -79,6 +101,11 @@ func main() {
 		Database: cfg.RedisDB,
 	})
 
+	go purgeDeletedUsers(userRepository.NewUserRepository(db), cfg.AccountDeletionGracePeriod)
+	if cfg.AuditRetention > 0 {
+		go purgeAuditEvents(audit.New(db), cfg.AuditRetention)
+	}
+
 	go func() {
 		httpSvr := httpServer.NewServer(validator, db, cache)
 		if err = httpSvr.Run(); err != nil {
@@ -92,3 +119,38 @@ func main() {
 	}
 
 }
+
+// purgeDeletedUsers removes deleted accounts for good once their grace period
+// is over.
+func purgeDeletedUsers(repo userRepository.IUserRepository, grace time.Duration) {
+	ticker := time.NewTicker(config.AccountPurgeInterval)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		purged, err := repo.PurgeDeleted(context.Background(), time.Now().Add(-grace))
+		if err != nil {
+			logger.Error("Failed to purge deleted users ", err)
+			continue
+		}
+		if purged > 0 {
+			logger.Infof("Purged %d deleted users", purged)
+		}
+	}
+}
+
+// purgeAuditEvents removes audit events older than the retention period.
+func purgeAuditEvents(log audit.IAudit, retention time.Duration) {
+	ticker := time.NewTicker(config.AuditPurgeInterval)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		purged, err := log.Purge(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			logger.Error("Failed to purge audit events ", err)
+			continue
+		}
+		if purged > 0 {
+			logger.Infof("Purged %d audit events", purged)
+		}
+	}
+}
@@ -24,12 +24,15 @@ type Address struct {
 	// Street of the address
 	// example: "Market Street"
 	Street string `json:"street"`
-	// Latitude of the address
-	// example: "37.7749"
-	Lat string `json:"lat"`
-	// Longitude of the address
-	// example: "-122.4194"
-	Long string `json:"long"`
+	// Latitude of the address, null when it has no coordinates
+	// example: 37.7749
+	Lat *float64 `json:"lat"`
+	// Longitude of the address, null when it has no coordinates
+	// example: -122.4194
+	Long *float64 `json:"long"`
+	// Whether this is the default shipping address of the user
+	// example: true
+	IsDefault bool `json:"is_default"`
 }
 
 // ***************************************************************************\\
@@ -37,9 +40,10 @@ type Address struct {
 // CreateAddressReq represents the request body for creating a new address.
 // swagger:model CreateAddressReq
 type CreateAddressReq struct {
-	// User ID associated with the address
+	// User ID associated with the address. Only admins acting on all users
+	// may set it, the address belongs to the caller otherwise.
 	// example: "67890"
-	IDUser string `json:"id_user"`
+	IDUser string `json:"id_user" validate:"required"`
 	// Name of the address
 	// example: "Home"
 	Name string `json:"name"`
@@ -49,12 +53,16 @@ type CreateAddressReq struct {
 	// Street of the address
 	// example: "Market Street"
 	Street string `json:"street"`
-	// Latitude of the address
-	// example: "37.7749"
-	Lat string `json:"lat"`
-	// Longitude of the address
-	// example: "-122.4194"
-	Long string `json:"long"`
+	// Latitude of the address, between -90 and 90. Set together with long.
+	// example: 37.7749
+	Lat *float64 `json:"lat" validate:"required_with=Long,omitempty,gte=-90,lte=90"`
+	// Longitude of the address, between -180 and 180. Set together with lat.
+	// example: -122.4194
+	Long *float64 `json:"long" validate:"required_with=Lat,omitempty,gte=-180,lte=180"`
+	// Make it the default shipping address of the user. The first address of
+	// a user is always the default.
+	// example: false
+	IsDefault bool `json:"is_default"`
 }
 
 // ***************************************************************************\\
@@ -62,12 +70,6 @@ type CreateAddressReq struct {
 // UpdateAddressReq represents the request body for updating an existing address.
 // swagger:model UpdateAddressReq
 type UpdateAddressReq struct {
-	// ID of the address
-	// example: "12345"
-	ID string `json:"id"`
-	// User ID associated with the address
-	// example: "67890"
-	IDUser string `json:"id_user"`
 	// Name of the address
 	// example: "Home"
 	Name string `json:"name"`
@@ -77,12 +79,12 @@ type UpdateAddressReq struct {
 	// Street of the address
 	// example: "Market Street"
 	Street string `json:"street"`
-	// Latitude of the address
-	// example: "37.7749"
-	Lat string `json:"lat"`
-	// Longitude of the address
-	// example: "-122.4194"
-	Long string `json:"long"`
+	// Latitude of the address, between -90 and 90. Set together with long.
+	// example: 37.7749
+	Lat *float64 `json:"lat" validate:"required_with=Long,omitempty,gte=-90,lte=90"`
+	// Longitude of the address, between -180 and 180. Set together with lat.
+	// example: -122.4194
+	Long *float64 `json:"long" validate:"required_with=Lat,omitempty,gte=-180,lte=180"`
 }
 
 // ***************************************************************************\\
@@ -93,9 +95,10 @@ type ListAddressReq struct {
 	// Name of the address
 	// example: "Home"
 	Name string `json:"name,omitempty" form:"name"`
-	// User ID associated with the address
+	// User ID associated with the address. Only admins acting on all users
+	// may filter by it, the caller's addresses are listed otherwise.
 	// example: "67890"
-	IDUser string `json:"id_user"`
+	IDUser string `json:"id_user" form:"id_user"`
 	// Page number for pagination
 	// example: 1
 	Page int64 `json:"-" form:"page"`
@@ -108,7 +111,7 @@ type ListAddressReq struct {
 // swagger:model ListAddressRes
 type ListAddressRes struct {
 	// List of addresses
-	// example: [{"id_address":"12345","id_user":"67890","name":"Home","city":"San Francisco","street":"Market Street","lat":"37.7749","long":"-122.4194"}]
+	// example: [{"id_address":"12345","id_user":"67890","name":"Home","city":"San Francisco","street":"Market Street","lat":37.7749,"long":-122.4194}]
 	Addresses []*Address `json:"addresses"`
 	// Pagination info
 	Pagination *paging.Pagination `json:"pagination"`
@@ -116,15 +119,48 @@ type ListAddressRes struct {
 
 // ***************************************************************************\\
 // ***************************************************************************\\
-// DeleteAddressReq represents the request body for deleting an address.
-// swagger:model DeleteAddressReq
-type DeleteAddressReq struct {
-	// ID of the address
-	// example: "12345"
-	ID string `json:"id"`
-	// User ID associated with the address
+// ListNearbyAddressReq represents the query parameters for finding the
+// addresses around a point.
+// swagger:model ListNearbyAddressReq
+type ListNearbyAddressReq struct {
+	// Latitude of the point
+	// example: 37.7749
+	Lat *float64 `json:"lat" form:"lat" validate:"required,gte=-90,lte=90"`
+	// Longitude of the point
+	// example: -122.4194
+	Long *float64 `json:"long" form:"long" validate:"required,gte=-180,lte=180"`
+	// Radius around the point in kilometers
+	// example: 5
+	RadiusKm float64 `json:"radius_km" form:"radius_km" validate:"gt=0,lte=20016"`
+	// User ID associated with the address. Only admins acting on all users
+	// may filter by it, the caller's addresses are searched otherwise.
 	// example: "67890"
-	IDUser string `json:"id_user"`
+	IDUser string `json:"id_user" form:"id_user"`
+	// Page number for pagination
+	// example: 1
+	Page int64 `json:"-" form:"page"`
+	// Limit number of items per page
+	// example: 10
+	Limit int64 `json:"-" form:"limit"`
+}
+
+// NearbyAddress represents an address found around a point.
+// swagger:model NearbyAddress
+type NearbyAddress struct {
+	Address
+	// Great-circle distance to the point in kilometers
+	// example: 1.2
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// ListNearbyAddressRes represents the response body for finding the
+// addresses around a point, nearest first.
+// swagger:model ListNearbyAddressRes
+type ListNearbyAddressRes struct {
+	// List of addresses, nearest first
+	Addresses []*NearbyAddress `json:"addresses"`
+	// Pagination info
+	Pagination *paging.Pagination `json:"pagination"`
 }
 
 //***************************************************************************\\
@@ -7,17 +7,21 @@ import (
 	"gorm.io/gorm"
 )
 
-// Address represents the domain model for an address.
+// Address represents the domain model for an address. A user has at most one
+// default address, see IsDefault. Lat and Long are nil for addresses without
+// coordinates.
 type Address struct {
-	ID        string    `json:"id_address"`
-	IDUser    string    `json:"id_user"`
-	Name      string    `json:"name"`
-	City      string    `json:"city"`
-	Street    string    `json:"street"`
-	Lat       string    `json:"lat"`
-	Long      string    `json:"long"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string         `json:"id_address"`
+	IDUser    string         `json:"id_user"`
+	Name      string         `json:"name"`
+	City      string         `json:"city"`
+	Street    string         `json:"street"`
+	Lat       *float64       `json:"lat"`
+	Long      *float64       `json:"long"`
+	IsDefault bool           `json:"is_default" gorm:"not null;default:false"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 func (m *Address) BeforeCreate(tx *gorm.DB) error {
@@ -30,3 +34,9 @@ func (m *Address) BeforeUpdate(tx *gorm.DB) error {
 	m.UpdatedAt = time.Now()
 	return nil
 }
+
+// NearbyAddress is an address found around a point, DistanceKm away from it.
+type NearbyAddress struct {
+	Address
+	DistanceKm float64 `json:"distance_km"`
+}
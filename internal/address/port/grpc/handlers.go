@@ -2,13 +2,17 @@ package grpc
 
 import (
 	"context"
-	"time"
+	"errors"
+	"fmt"
 
 	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"main/internal/address/dto"
 	"main/internal/address/service"
 	"main/pkg/config"
+	"main/pkg/middleware"
 	"main/pkg/redis"
 	"main/pkg/utils"
 	pb "main/proto/gen/go/address"
@@ -37,157 +41,288 @@ func NewAddressHandler(
 //	}
 
 func (h *AddressHandler) GetAddressByID(ctx context.Context, req *pb.GetAddressByIDRequest) (*pb.AddressResponse, error) {
+	owner, err := addressOwner(ctx, req.All)
+	if err != nil {
+		return nil, err
+	}
+
 	var res dto.Address
-	cacheKey := "address_" + req.Id
-	err := h.cache.Get(cacheKey, &res)
+	cacheKey := cacheKey(owner, "id:"+req.Id)
+	err = h.cache.Get(cacheKey, &res)
 	if err == nil {
-		return &pb.AddressResponse{Address: &pb.Address{
-			IdAddress: res.ID,
-			IdUser:    res.IDUser,
-			Name:      res.Name,
-			City:      res.City,
-			Street:    res.Street,
-			Lat:       res.Lat,
-			Long:      res.Long,
-
-			// CreatedAt: formatTimeToString(res.CreatedAt),
-			// UpdatedAt: formatTimeToString(res.UpdatedAt),
-		}}, nil
+		return &pb.AddressResponse{Address: toPB(&res)}, nil
 	}
 
-	address, err := h.service.GetAddressByID(ctx, req.Id)
+	address, err := h.service.GetAddressByID(ctx, owner, req.Id)
 	if err != nil {
 		logger.Error("Failed to get address detail: ", err)
-		return nil, err
+		return nil, addressError(err)
 	}
 
 	utils.Copy(&res, &address)
 	_ = h.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime.Abs())
-	return &pb.AddressResponse{Address: &pb.Address{
-		IdAddress: res.ID,
-		IdUser:    res.IDUser,
-		Name:      res.Name,
-		City:      res.City,
-		Street:    res.Street,
-		Lat:       res.Lat,
-		Long:      res.Long,
-	}}, nil
+	return &pb.AddressResponse{Address: toPB(&res)}, nil
 }
 
 func (h *AddressHandler) ListAddresses(ctx context.Context, req *pb.ListAddressesRequest) (*pb.ListAddressesResponse, error) {
+	owner, err := addressOwner(ctx, req.All)
+	if err != nil {
+		return nil, err
+	}
+
+	var listReq dto.ListAddressReq
+	if req.Request != nil {
+		listReq = dto.ListAddressReq{
+			Name:   req.Request.Name,
+			IDUser: req.Request.IdUser,
+			Page:   req.Request.Page,
+			Limit:  req.Request.Limit,
+		}
+	}
+
 	var res dto.ListAddressRes
-	cacheKey := "addresses_list"
-	err := h.cache.Get(cacheKey, &res)
-	if err == nil {
-		var pbAddresses []*pb.Address
-		for _, addr := range res.Addresses {
-			pbAddresses = append(pbAddresses, &pb.Address{
-				IdAddress: addr.ID,
-				IdUser:    addr.IDUser,
-				Name:      addr.Name,
-				City:      addr.City,
-				Street:    addr.Street,
-				Lat:       addr.Lat,
-				Long:      addr.Long,
-			})
+	cacheKey := cacheKey(owner, fmt.Sprintf("list:%s:%s:%d:%d", listReq.Name, listReq.IDUser, listReq.Page, listReq.Limit))
+	err = h.cache.Get(cacheKey, &res)
+	if err != nil {
+		addresses, pagination, err := h.service.ListAddresses(ctx, owner, &listReq)
+		if err != nil {
+			logger.Error("Failed to get list of addresses: ", err)
+			return nil, addressError(err)
 		}
-		return &pb.ListAddressesResponse{Addresses: pbAddresses}, nil
+
+		utils.Copy(&res.Addresses, &addresses)
+		res.Pagination = pagination
+		_ = h.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime)
 	}
 
-	addresses, pagination, err := h.service.ListAddresses(ctx, &dto.ListAddressReq{})
+	var pbAddresses []*pb.Address
+	for _, addr := range res.Addresses {
+		pbAddresses = append(pbAddresses, toPB(addr))
+	}
+	var pagination *pb.Pagination
+	if res.Pagination != nil {
+		pagination = &pb.Pagination{
+			Total: res.Pagination.Total,
+			Page:  res.Pagination.CurrentPage,
+			Limit: res.Pagination.Limit,
+		}
+	}
+	return &pb.ListAddressesResponse{Addresses: pbAddresses, Pagination: pagination}, nil
+}
+
+func (h *AddressHandler) ListNearbyAddresses(ctx context.Context, req *pb.ListNearbyAddressesRequest) (*pb.ListNearbyAddressesResponse, error) {
+	owner, err := addressOwner(ctx, req.All)
 	if err != nil {
-		logger.Error("Failed to get list of addresses: ", err)
 		return nil, err
 	}
 
-	utils.Copy(&res.Addresses, &addresses)
-	res.Pagination = pagination
-	_ = h.cache.SetWithExpiration(cacheKey, res, time.Hour) // Adjust caching time as needed
+	nearbyReq := dto.ListNearbyAddressReq{
+		Lat:      &req.Lat,
+		Long:     &req.Long,
+		RadiusKm: req.RadiusKm,
+		IDUser:   req.IdUser,
+		Page:     req.Page,
+		Limit:    req.Limit,
+	}
 
-	var pbAddresses []*pb.Address
-	for _, addr := range addresses {
-		pbAddresses = append(pbAddresses, &pb.Address{
-			IdAddress: addr.ID,
-			IdUser:    addr.IDUser,
-			Name:      addr.Name,
-			City:      addr.City,
-			Street:    addr.Street,
-			Lat:       addr.Lat,
-			Long:      addr.Long,
+	var res dto.ListNearbyAddressRes
+	cacheKey := cacheKey(owner, fmt.Sprintf("nearby:%g:%g:%g:%s:%d:%d", req.Lat, req.Long, req.RadiusKm, req.IdUser, req.Page, req.Limit))
+	err = h.cache.Get(cacheKey, &res)
+	if err != nil {
+		addresses, pagination, err := h.service.ListNearby(ctx, owner, &nearbyReq)
+		if err != nil {
+			logger.Error("Failed to find nearby addresses: ", err)
+			return nil, addressError(err)
+		}
+
+		utils.Copy(&res.Addresses, &addresses)
+		res.Pagination = pagination
+		_ = h.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime)
+	}
+
+	var pbAddresses []*pb.NearbyAddress
+	for _, addr := range res.Addresses {
+		pbAddresses = append(pbAddresses, &pb.NearbyAddress{
+			Address:    toPB(&addr.Address),
+			DistanceKm: addr.DistanceKm,
 		})
 	}
-	return &pb.ListAddressesResponse{Addresses: pbAddresses}, nil
+	var pagination *pb.Pagination
+	if res.Pagination != nil {
+		pagination = &pb.Pagination{
+			Total: res.Pagination.Total,
+			Page:  res.Pagination.CurrentPage,
+			Limit: res.Pagination.Limit,
+		}
+	}
+	return &pb.ListNearbyAddressesResponse{Addresses: pbAddresses, Pagination: pagination}, nil
 }
 
 func (h *AddressHandler) CreateAddress(ctx context.Context, req *pb.CreateAddressRequest) (*pb.AddressResponse, error) {
+	owner, err := addressOwner(ctx, req.All)
+	if err != nil {
+		return nil, err
+	}
+	if req.Request == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing request")
+	}
+
 	var addressDTO dto.CreateAddressReq
 	addressDTO.IDUser = req.Request.IdUser
 	addressDTO.Name = req.Request.Name
 	addressDTO.City = req.Request.City
 	addressDTO.Street = req.Request.Street
-	addressDTO.Lat = req.Request.Lat
-	addressDTO.Long = req.Request.Long
+	addressDTO.Lat = req.Request.Latitude
+	addressDTO.Long = req.Request.Longitude
+	addressDTO.IsDefault = req.Request.IsDefault
 
-	address, err := h.service.Create(ctx, &addressDTO)
+	address, err := h.service.Create(ctx, owner, &addressDTO)
 	if err != nil {
 		logger.Error("Failed to create address: ", err)
-		return nil, err
+		return nil, addressError(err)
 	}
 
 	var res dto.Address
 	utils.Copy(&res, &address)
-	_ = h.cache.RemovePattern("*address*")
-	return &pb.AddressResponse{Address: &pb.Address{
-		IdAddress: res.ID,
-		IdUser:    res.IDUser,
-		Name:      res.Name,
-		City:      res.City,
-		Street:    res.Street,
-		Lat:       res.Lat,
-		Long:      res.Long,
-	}}, nil
+	_ = h.cache.RemovePattern(cachePattern)
+	return &pb.AddressResponse{Address: toPB(&res)}, nil
 }
 
 func (h *AddressHandler) UpdateAddress(ctx context.Context, req *pb.UpdateAddressRequest) (*pb.AddressResponse, error) {
+	owner, err := addressOwner(ctx, req.All)
+	if err != nil {
+		return nil, err
+	}
+	if req.Request == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing request")
+	}
+
 	var addressDTO dto.UpdateAddressReq
 	addressDTO.Name = req.Request.Name
 	addressDTO.City = req.Request.City
 	addressDTO.Street = req.Request.Street
-	addressDTO.Lat = req.Request.Lat
-	addressDTO.Long = req.Request.Long
+	addressDTO.Lat = req.Request.Latitude
+	addressDTO.Long = req.Request.Longitude
 
-	address, err := h.service.Update(ctx, req.Id, &addressDTO)
+	address, err := h.service.Update(ctx, owner, req.Id, &addressDTO)
 	if err != nil {
 		logger.Error("Failed to update address: ", err)
-		return nil, err
+		return nil, addressError(err)
 	}
 
 	var res dto.Address
 	utils.Copy(&res, &address)
-	_ = h.cache.RemovePattern("*address*")
-	return &pb.AddressResponse{Address: &pb.Address{
-		IdAddress: res.ID,
-		IdUser:    res.IDUser,
-		Name:      res.Name,
-		City:      res.City,
-		Street:    res.Street,
-		Lat:       res.Lat,
-		Long:      res.Long,
-	}}, nil
+	_ = h.cache.RemovePattern(cachePattern)
+	return &pb.AddressResponse{Address: toPB(&res)}, nil
 }
 
 func (h *AddressHandler) DeleteAddress(ctx context.Context, req *pb.DeleteAddressRequest) (*pb.AddressResponse, error) {
-	address, err := h.service.Delete(ctx, req.Id, &dto.DeleteAddressReq{})
+	owner, err := addressOwner(ctx, req.All)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := h.service.Delete(ctx, owner, req.Id)
 	if err != nil {
 		logger.Error("Failed to delete address: ", err)
+		return nil, addressError(err)
+	}
+
+	var res dto.Address
+	utils.Copy(&res, &address)
+	_ = h.cache.RemovePattern(cachePattern)
+	return &pb.AddressResponse{Address: toPB(&res)}, nil
+}
+
+func (h *AddressHandler) GetDefaultAddress(ctx context.Context, req *pb.GetDefaultAddressRequest) (*pb.AddressResponse, error) {
+	owner, err := addressOwner(ctx, req.All)
+	if err != nil {
+		return nil, err
+	}
+	if owner == "" {
+		owner = req.IdUser
+	}
+	if owner == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing id_user")
+	}
+
+	var res dto.Address
+	cacheKey := cacheKey(owner, "default")
+	err = h.cache.Get(cacheKey, &res)
+	if err == nil {
+		return &pb.AddressResponse{Address: toPB(&res)}, nil
+	}
+
+	address, err := h.service.GetDefault(ctx, owner)
+	if err != nil {
+		logger.Error("Failed to get default address: ", err)
+		return nil, addressError(err)
+	}
+
+	utils.Copy(&res, &address)
+	_ = h.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime)
+	return &pb.AddressResponse{Address: toPB(&res)}, nil
+}
+
+func (h *AddressHandler) SetDefaultAddress(ctx context.Context, req *pb.SetDefaultAddressRequest) (*pb.AddressResponse, error) {
+	owner, err := addressOwner(ctx, req.All)
+	if err != nil {
 		return nil, err
 	}
 
+	address, err := h.service.SetDefault(ctx, owner, req.Id)
+	if err != nil {
+		logger.Error("Failed to set default address: ", err)
+		return nil, addressError(err)
+	}
+
 	var res dto.Address
 	utils.Copy(&res, &address)
-	_ = h.cache.RemovePattern("*address*")
-	return &pb.AddressResponse{Address: &pb.Address{
-		IdAddress: req.Request.Id,
-		IdUser:    req.Request.IdUser,
-	}}, nil
+	_ = h.cache.RemovePattern(cachePattern)
+	return &pb.AddressResponse{Address: toPB(&res)}, nil
+}
+
+// cachePattern matches every cached address response, see cacheKey.
+const cachePattern = "address:*"
+
+// cacheKey keeps the cached responses of different owners apart. Every kind
+// of response uses its own key prefix, such as "id:" or "list:", so that an
+// address id cannot be mistaken for another key. HTTP responses are cached by
+// request URI, which starts with a slash.
+func cacheKey(owner, key string) string {
+	return "address:" + owner + ":" + key
+}
+
+// addressOwner scopes the call to the authenticated user. Admins lift the
+// scoping explicitly with all.
+func addressOwner(ctx context.Context, all bool) (string, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	return service.Owner(principal.UserID, principal.Role, all), nil
+}
+
+func addressError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrAddressNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidAddress):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
+
+func toPB(address *dto.Address) *pb.Address {
+	return &pb.Address{
+		IdAddress: address.ID,
+		IdUser:    address.IDUser,
+		Name:      address.Name,
+		City:      address.City,
+		Street:    address.Street,
+		Latitude:  address.Lat,
+		Longitude: address.Long,
+		IsDefault: address.IsDefault,
+	}
 }
@@ -6,14 +6,16 @@ import (
 
 	"main/internal/address/repository"
 	"main/internal/address/service"
+	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/geocoder"
 	"main/pkg/redis"
 	pb "main/proto/gen/go/address"
 )
 
 func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation, cache redis.IRedis) {
 	AddressRepo := repository.NewAddressRepository(db)
-	AddressSvc := service.NewAddressService(validator, AddressRepo)
+	AddressSvc := service.NewAddressService(validator, AddressRepo, geocoder.NewFromConfig(config.GetConfig(), cache))
 	AddressHandler := NewAddressHandler(cache, AddressSvc)
 
 	pb.RegisterAddressServiceServer(svr, AddressHandler)
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,6 +11,7 @@ import (
 	"main/internal/address/dto"
 	"main/internal/address/service"
 	"main/pkg/config"
+	"main/pkg/middleware"
 	"main/pkg/redis"
 	"main/pkg/response"
 	"main/pkg/utils"
@@ -37,26 +39,33 @@ func NewAddressHandler(
 //	@Summary	Get Address by id
 //	@Tags		Address
 //	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
 //	@Param		id	path	string	true	"Address ID"
+//	@Param		all	query	bool	false	"Admins only: look at the addresses of every user"
 //	@Success	200	{object}	dto.Address
 //	@Router		/address/{id} [get]
 func (p *AddressHandler) GetAddressByID(c *gin.Context) {
-
-	AddressId := c.Param("id")
-	Address, err := p.service.GetAddressByID(c, AddressId)
-	if err != nil {
-		logger.Error("Failed to get Address detail: ", err)
-		response.Error(c, http.StatusNotFound, err, "Not found")
+	owner, ok := addressOwner(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
 		return
 	}
 
 	var res dto.Address
-	cacheKey := c.Request.URL.RequestURI()
-	err2 := p.cache.Get(cacheKey, &res)
-	if err2 == nil {
+	cacheKey := cacheKey(owner, c.Request.URL.RequestURI())
+	if err := p.cache.Get(cacheKey, &res); err == nil {
 		response.JSON(c, http.StatusOK, res)
 		return
 	}
+
+	Address, err := p.service.GetAddressByID(c, owner, c.Param("id"))
+	if err != nil {
+		logger.Error("Failed to get Address detail: ", err)
+		addressError(c, err)
+		return
+	}
+
 	utils.Copy(&res, &Address)
 	response.JSON(c, http.StatusOK, res)
 	_ = p.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime.Abs())
@@ -64,59 +73,94 @@ func (p *AddressHandler) GetAddressByID(c *gin.Context) {
 
 // ListAddress godoc
 //
-//		@Summary	Get list Address
-//		@Tags		Address
-//		@Produce	json
-//	 @Param id_user header string true "id_user"
-//	@Param		name	path	string					true	"name"
-//	@Param		page	path	string					true	"page"
-//	@Param		limit	path	string					true	"limit"
-//
-// @Success	200	{object}	dto.ListAddressRes
-// @Router		/address  [get]
+//	@Summary	Get list Address
+//	@Tags		Address
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Param		name	query	string	false	"name"
+//	@Param		page	query	int		false	"page"
+//	@Param		limit	query	int		false	"limit"
+//	@Param		all		query	bool	false	"Admins only: list the addresses of every user"
+//	@Param		id_user	query	string	false	"Admins only, with all: list the addresses of this user"
+//	@Success	200	{object}	dto.ListAddressRes
+//	@Router		/address [get]
 func (p *AddressHandler) ListAddresses(c *gin.Context) {
-	Name := c.Param("name")
-	IDUser := c.Param("id_user")
-	PageStr := c.Param("page")
-	LimitStr := c.Param("limit")
+	owner, ok := addressOwner(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	var req dto.ListAddressReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to parse request query: ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
 
-	Page, err1 := strconv.ParseInt(PageStr, 10, 64)
-	if err1 != nil {
-		c.JSON(http.StatusBadRequest, HTTPError{Code: http.StatusBadRequest, Message: "Invalid page number"})
+	var res dto.ListAddressRes
+	cacheKey := cacheKey(owner, c.Request.URL.RequestURI())
+	err := p.cache.Get(cacheKey, &res)
+	if err == nil {
+		response.JSON(c, http.StatusOK, res)
 		return
 	}
 
-	Limit, err2 := strconv.ParseInt(LimitStr, 10, 64)
-	if err2 != nil {
-		c.JSON(http.StatusBadRequest, HTTPError{Code: http.StatusBadRequest, Message: "Invalid limit number"})
+	Addresses, pagination, err := p.service.ListAddresses(c, owner, &req)
+	if err != nil {
+		logger.Error("Failed to get list Address: ", err)
+		addressError(c, err)
 		return
 	}
 
-	// var req dto.ListAddressReq
-	req := dto.ListAddressReq{
-		Name:   Name,
-		IDUser: IDUser,
-		Page:   Page,
-		Limit:  Limit,
+	utils.Copy(&res.Addresses, &Addresses)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+	_ = p.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime)
+}
+
+// ListNearbyAddresses godoc
+//
+//	@Summary	Find the addresses around a point, nearest first
+//	@Tags		Address
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Param		lat			query	number	true	"Latitude of the point"
+//	@Param		long		query	number	true	"Longitude of the point"
+//	@Param		radius_km	query	number	true	"Radius around the point in kilometers"
+//	@Param		page		query	int		false	"page"
+//	@Param		limit		query	int		false	"limit"
+//	@Param		all			query	bool	false	"Admins only: search the addresses of every user"
+//	@Param		id_user		query	string	false	"Admins only, with all: search the addresses of this user"
+//	@Success	200	{object}	dto.ListNearbyAddressRes
+//	@Router		/address/nearby [get]
+func (p *AddressHandler) ListNearbyAddresses(c *gin.Context) {
+	owner, ok := addressOwner(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
 	}
+
+	var req dto.ListNearbyAddressReq
 	if err := c.ShouldBindQuery(&req); err != nil {
 		logger.Error("Failed to parse request query: ", err)
 		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
 		return
 	}
 
-	var res dto.ListAddressRes
-	cacheKey := c.Request.URL.RequestURI()
-	err := p.cache.Get(cacheKey, &res)
-	if err == nil {
+	var res dto.ListNearbyAddressRes
+	cacheKey := cacheKey(owner, c.Request.URL.RequestURI())
+	if err := p.cache.Get(cacheKey, &res); err == nil {
 		response.JSON(c, http.StatusOK, res)
 		return
 	}
 
-	Addresses, pagination, err := p.service.ListAddresses(c, &req)
+	Addresses, pagination, err := p.service.ListNearby(c, owner, &req)
 	if err != nil {
-		logger.Error("Failed to get list Address: ", err)
-		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		logger.Error("Failed to find nearby Addresses: ", err)
+		addressError(c, err)
 		return
 	}
 
@@ -132,10 +176,18 @@ func (p *AddressHandler) ListAddresses(c *gin.Context) {
 //	@Tags		Address
 //	@Produce	json
 //	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Param		all	query	bool					false	"Admins only: create the address for id_user"
 //	@Param		_	body	dto.CreateAddressReq	true	"Body"
 //	@Success	200	{object}	dto.Address
 //	@Router		/address [post]
 func (p *AddressHandler) CreateAddress(c *gin.Context) {
+	owner, ok := addressOwner(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
 	var req dto.CreateAddressReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
 		logger.Error("Failed to get body", err)
@@ -143,17 +195,17 @@ func (p *AddressHandler) CreateAddress(c *gin.Context) {
 		return
 	}
 
-	Address, err := p.service.Create(c, &req)
+	Address, err := p.service.Create(c, owner, &req)
 	if err != nil {
 		logger.Error("Failed to create Address", err.Error())
-		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		addressError(c, err)
 		return
 	}
 
 	var res dto.Address
 	utils.Copy(&res, &Address)
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*Address*")
+	_ = p.cache.RemovePattern(cachePattern)
 }
 
 // UpdateAddress godoc
@@ -162,12 +214,19 @@ func (p *AddressHandler) CreateAddress(c *gin.Context) {
 //	@Tags		Address
 //	@Produce	json
 //	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Param		id	path	string					true	"Address ID"
+//	@Param		all	query	bool					false	"Admins only: update the address of any user"
 //	@Param		_	body	dto.UpdateAddressReq	true	"Body"
 //	@Success	200	{object}	dto.Address
 //	@Router		/address/{id} [put]
 func (p *AddressHandler) UpdateAddress(c *gin.Context) {
-	//	@Param		id	path	string					true	"Address ID"
-	// AddressId := c.Param("id")
+	owner, ok := addressOwner(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
 	var req dto.UpdateAddressReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
 		logger.Error("Failed to get body", err)
@@ -175,17 +234,17 @@ func (p *AddressHandler) UpdateAddress(c *gin.Context) {
 		return
 	}
 
-	Address, err := p.service.Update(c, req.ID, &req)
+	Address, err := p.service.Update(c, owner, c.Param("id"), &req)
 	if err != nil {
 		logger.Error("Failed to Update Address", err.Error())
-		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		addressError(c, err)
 		return
 	}
 
 	var res dto.Address
 	utils.Copy(&res, &Address)
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*Address*")
+	_ = p.cache.RemovePattern(cachePattern)
 }
 
 // DeleteAddress godoc
@@ -194,30 +253,135 @@ func (p *AddressHandler) UpdateAddress(c *gin.Context) {
 //	@Tags		Address
 //	@Produce	json
 //	@Security	ApiKeyAuth
-//	@Param		_	body	dto.DeleteAddressReq	true	"Body"
+//	@Security	MachineKeyAuth
+//	@Param		id	path	string	true	"Address ID"
+//	@Param		all	query	bool	false	"Admins only: delete the address of any user"
 //	@Success	200	{object}	dto.Address
-//	@Router		/address/{id} [Delete]
+//	@Router		/address/{id} [delete]
 func (p *AddressHandler) DeleteAddress(c *gin.Context) {
-	//	@Param		id	path	string					true	"Address ID"
-	// AddressId := c.Param("id")
-	var req dto.DeleteAddressReq
-	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
-		logger.Error("Failed to get body", err)
-		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+	owner, ok := addressOwner(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
 		return
 	}
 
-	Address, err := p.service.Delete(c, req.ID, &req)
+	Address, err := p.service.Delete(c, owner, c.Param("id"))
 	if err != nil {
 		logger.Error("Failed to Delete Address", err.Error())
-		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		addressError(c, err)
 		return
 	}
 
 	var res dto.Address
 	utils.Copy(&res, &Address)
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*Address*")
+	_ = p.cache.RemovePattern(cachePattern)
+}
+
+// GetDefaultAddress godoc
+//
+//	@Summary	Get the default shipping address
+//	@Tags		Address
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Param		all		query	bool	false	"Admins only: look at the default address of id_user"
+//	@Param		id_user	query	string	false	"Admins only, with all: the user to look at"
+//	@Success	200	{object}	dto.Address
+//	@Router		/address/default [get]
+func (p *AddressHandler) GetDefaultAddress(c *gin.Context) {
+	owner, ok := addressOwner(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+	if owner == "" {
+		owner = c.Query("id_user")
+	}
+	if owner == "" {
+		response.Error(c, http.StatusBadRequest, errors.New("missing id_user"), "Invalid parameters")
+		return
+	}
+
+	var res dto.Address
+	cacheKey := cacheKey(owner, c.Request.URL.RequestURI())
+	if err := p.cache.Get(cacheKey, &res); err == nil {
+		response.JSON(c, http.StatusOK, res)
+		return
+	}
+
+	Address, err := p.service.GetDefault(c, owner)
+	if err != nil {
+		logger.Error("Failed to get default Address: ", err)
+		addressError(c, err)
+		return
+	}
+
+	utils.Copy(&res, &Address)
+	response.JSON(c, http.StatusOK, res)
+	_ = p.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime)
+}
+
+// SetDefaultAddress godoc
+//
+//	@Summary	Make an address the default shipping address
+//	@Tags		Address
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Param		id	path	string	true	"Address ID"
+//	@Param		all	query	bool	false	"Admins only: change the default address of any user"
+//	@Success	200	{object}	dto.Address
+//	@Router		/address/{id}/default [put]
+func (p *AddressHandler) SetDefaultAddress(c *gin.Context) {
+	owner, ok := addressOwner(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	Address, err := p.service.SetDefault(c, owner, c.Param("id"))
+	if err != nil {
+		logger.Error("Failed to set default Address", err.Error())
+		addressError(c, err)
+		return
+	}
+
+	var res dto.Address
+	utils.Copy(&res, &Address)
+	response.JSON(c, http.StatusOK, res)
+	_ = p.cache.RemovePattern(cachePattern)
+}
+
+// cachePattern matches every cached address response, see cacheKey.
+const cachePattern = "address:*"
+
+// cacheKey keeps the cached responses of different owners apart.
+func cacheKey(owner, uri string) string {
+	return "address:" + owner + ":" + uri
+}
+
+// addressOwner scopes the request to the authenticated user. Admins lift the
+// scoping explicitly with ?all=true.
+func addressOwner(c *gin.Context) (string, bool) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		return "", false
+	}
+
+	all, _ := strconv.ParseBool(c.Query("all"))
+	return service.Owner(principal.UserID, principal.Role, all), true
+}
+
+func addressError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAddressNotFound):
+		response.Error(c, http.StatusNotFound, err, "Not found")
+	case errors.Is(err, service.ErrInvalidAddress):
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+	default:
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
 }
 
 // HTTPError represents an HTTP error
@@ -6,23 +6,31 @@ import (
 
 	"main/internal/address/repository"
 	"main/internal/address/service"
+	"main/pkg/apikey"
+	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/geocoder"
 	"main/pkg/middleware"
 	"main/pkg/redis"
 )
 
 func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, cache redis.IRedis) {
 	addressRepo := repository.NewAddressRepository(sqlDB)
-	addressSvc := service.NewAddressService(validator, addressRepo)
+	addressSvc := service.NewAddressService(validator, addressRepo, geocoder.NewFromConfig(config.GetConfig(), cache))
 	addressHandler := NewAddressHandler(cache, addressSvc)
 
-	authMiddleware := middleware.JWTAuth()
+	authMiddleware := middleware.JWTAuthOrAPIKey(cache, apikey.NewStore(sqlDB))
+	readScope := middleware.RequireScope(apikey.ScopeAddressesRead)
+	writeScope := middleware.RequireScope(apikey.ScopeAddressesWrite)
 	AddressRoute := r.Group("/address")
 	{
-		AddressRoute.GET("", addressHandler.ListAddresses)
-		AddressRoute.GET("/:id", addressHandler.GetAddressByID)
-		AddressRoute.POST("", authMiddleware, addressHandler.CreateAddress)
-		AddressRoute.PUT("/:id", authMiddleware, addressHandler.UpdateAddress)
-		AddressRoute.DELETE("/:id", authMiddleware, addressHandler.DeleteAddress)
+		AddressRoute.GET("", authMiddleware, readScope, addressHandler.ListAddresses)
+		AddressRoute.GET("/nearby", authMiddleware, readScope, addressHandler.ListNearbyAddresses)
+		AddressRoute.GET("/default", authMiddleware, readScope, addressHandler.GetDefaultAddress)
+		AddressRoute.GET("/:id", authMiddleware, readScope, addressHandler.GetAddressByID)
+		AddressRoute.POST("", authMiddleware, writeScope, addressHandler.CreateAddress)
+		AddressRoute.PUT("/:id", authMiddleware, writeScope, addressHandler.UpdateAddress)
+		AddressRoute.DELETE("/:id", authMiddleware, writeScope, addressHandler.DeleteAddress)
+		AddressRoute.PUT("/:id/default", authMiddleware, writeScope, addressHandler.SetDefaultAddress)
 	}
 }
@@ -2,11 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
+
+	"gorm.io/gorm"
 
 	"main/internal/address/dto"
 	"main/internal/address/model"
 	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/geo"
 	"main/pkg/paging"
 )
 
@@ -16,7 +20,10 @@ type IAddressRepository interface {
 	Delete(ctx context.Context, Address *model.Address) error
 	Update(ctx context.Context, Address *model.Address) error
 	ListAddresses(ctx context.Context, req *dto.ListAddressReq) ([]*model.Address, *paging.Pagination, error)
-	GetAddressByID(ctx context.Context, id string) (*model.Address, error)
+	ListNearby(ctx context.Context, req *dto.ListNearbyAddressReq) ([]*model.NearbyAddress, *paging.Pagination, error)
+	GetAddressByID(ctx context.Context, owner, id string) (*model.Address, error)
+	GetDefault(ctx context.Context, userID string) (*model.Address, error)
+	SetDefault(ctx context.Context, Address *model.Address) error
 }
 
 type AddressRepo struct {
@@ -40,6 +47,9 @@ func (r *AddressRepo) ListAddresses(ctx context.Context, req *dto.ListAddressReq
 	if req.Name != "" {
 		query = append(query, dbs.NewQuery("name LIKE ?", "%"+req.Name+"%"))
 	}
+	if req.IDUser != "" {
+		query = append(query, dbs.NewQuery("id_user = ?", req.IDUser))
+	}
 	// if req.Code != "" {
 	// 	query = append(query, dbs.NewQuery("code = ?", req.Code))
 	// }
@@ -74,21 +84,166 @@ func (r *AddressRepo) ListAddresses(ctx context.Context, req *dto.ListAddressReq
 	return Addresss, pagination, nil
 }
 
-func (r *AddressRepo) GetAddressByID(ctx context.Context, id string) (*model.Address, error) {
+// distanceKm is the haversine formula for the great-circle distance in
+// kilometers between an address and a point. Its arguments are the earth
+// radius, the latitude of the point twice and its longitude.
+const distanceKm = `2 * ? * asin(sqrt(least(1,
+	power(sin(radians(lat - ?) / 2), 2) +
+	cos(radians(?)) * cos(radians(lat)) * power(sin(radians(long - ?) / 2), 2))))`
+
+// ListNearby finds the addresses within req.RadiusKm of the point, nearest
+// first. Addresses without coordinates are left out.
+func (r *AddressRepo) ListNearby(ctx context.Context, req *dto.ListNearbyAddressReq) ([]*model.NearbyAddress, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+	defer cancel()
+
+	lat, long := *req.Lat, *req.Long
+	// Cheap bound on the latitude before computing any distance.
+	span := req.RadiusKm / geo.KmPerDegree
+	db := r.db.GetDB().WithContext(ctx)
+	candidates := db.Model(&model.Address{}).
+		Select("*, "+distanceKm+" AS distance_km", geo.EarthRadiusKm, lat, lat, long).
+		Where("lat IS NOT NULL AND long IS NOT NULL").
+		Where("lat BETWEEN ? AND ?", lat-span, lat+span)
+	if req.IDUser != "" {
+		candidates = candidates.Where("id_user = ?", req.IDUser)
+	}
+	nearby := func() *gorm.DB {
+		return db.Table("(?) AS nearby", candidates).Where("distance_km <= ?", req.RadiusKm)
+	}
+
+	var total int64
+	if err := nearby().Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var Addresses []*model.NearbyAddress
+	if err := nearby().
+		Order("distance_km, id").
+		Limit(int(pagination.Limit)).
+		Offset(int(pagination.Skip)).
+		Find(&Addresses).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return Addresses, pagination, nil
+}
+
+// GetAddressByID finds the address id of owner, or of any user when owner is
+// empty.
+func (r *AddressRepo) GetAddressByID(ctx context.Context, owner, id string) (*model.Address, error) {
+	query := []dbs.Query{dbs.NewQuery("id = ?", id)}
+	if owner != "" {
+		query = append(query, dbs.NewQuery("id_user = ?", owner))
+	}
+
+	var Address model.Address
+	if err := r.db.FindOne(ctx, &Address, dbs.WithQuery(query...)); err != nil {
+		return nil, err
+	}
+	return &Address, nil
+}
+
+// GetDefault finds the default address of userID.
+func (r *AddressRepo) GetDefault(ctx context.Context, userID string) (*model.Address, error) {
 	var Address model.Address
-	if err := r.db.FindById(ctx, id, &Address); err != nil {
+	if err := r.db.FindOne(
+		ctx,
+		&Address,
+		dbs.WithQuery(dbs.NewQuery("id_user = ? AND is_default", userID)),
+	); err != nil {
 		return nil, err
 	}
 	return &Address, nil
 }
 
+// Create adds the address. It becomes the default address of its user when
+// asked to, or when the user has none yet.
 func (r *AddressRepo) Create(ctx context.Context, Address *model.Address) error {
-	return r.db.Create(ctx, Address)
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOwner(tx, Address.IDUser); err != nil {
+			return err
+		}
+
+		if Address.IsDefault {
+			if err := clearDefault(tx, Address.IDUser); err != nil {
+				return err
+			}
+		} else {
+			var defaults int64
+			if err := tx.Model(&model.Address{}).
+				Where("id_user = ? AND is_default", Address.IDUser).
+				Count(&defaults).Error; err != nil {
+				return err
+			}
+			Address.IsDefault = defaults == 0
+		}
+
+		return tx.Create(Address).Error
+	})
 }
 
+// SetDefault makes the address the only default address of its user.
+func (r *AddressRepo) SetDefault(ctx context.Context, Address *model.Address) error {
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOwner(tx, Address.IDUser); err != nil {
+			return err
+		}
+		if err := clearDefault(tx, Address.IDUser); err != nil {
+			return err
+		}
+
+		Address.IsDefault = true
+		return tx.Model(Address).Update("is_default", true).Error
+	})
+}
+
+// Update saves the address. The default flag is left alone, only SetDefault
+// and Delete change it.
 func (r *AddressRepo) Update(ctx context.Context, Address *model.Address) error {
-	return r.db.Update(ctx, Address)
+	return r.db.GetDB().WithContext(ctx).Omit("is_default").Save(Address).Error
 }
+
+// Delete removes the address. When it was the default address of its user,
+// the most recently created address left takes over.
 func (r *AddressRepo) Delete(ctx context.Context, Address *model.Address) error {
-	return r.db.Delete(ctx, Address)
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOwner(tx, Address.IDUser); err != nil {
+			return err
+		}
+		if err := tx.Delete(Address).Error; err != nil {
+			return err
+		}
+
+		var defaults int64
+		if err := tx.Model(&model.Address{}).
+			Where("id_user = ? AND is_default", Address.IDUser).
+			Count(&defaults).Error; err != nil || defaults > 0 {
+			return err
+		}
+
+		var next model.Address
+		err := tx.Where("id_user = ?", Address.IDUser).Order("created_at DESC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+// lockOwner serializes the changes to the addresses of userID until the
+// transaction ends, so that concurrent changes cannot leave two defaults.
+func lockOwner(tx *gorm.DB, userID string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "address:"+userID).Error
+}
+
+func clearDefault(tx *gorm.DB, userID string) error {
+	return tx.Model(&model.Address{}).
+		Where("id_user = ? AND is_default", userID).
+		Update("is_default", false).Error
 }
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"main/internal/address/model"
+	"main/pkg/dbs"
+	"main/pkg/geo"
+)
+
+// CoordinateFailure is an address whose text coordinates could not be parsed
+// by MigrateCoordinates. Its coordinates are cleared.
+type CoordinateFailure struct {
+	ID     string
+	Lat    string
+	Long   string
+	Reason string
+}
+
+// MigrateCoordinates turns the lat and long text columns of the addresses
+// into numbers. It must run before AutoMigrate, and does nothing once the
+// columns are numeric. Rows it cannot parse lose their coordinates and are
+// reported.
+func MigrateCoordinates(ctx context.Context, db dbs.IDatabase) ([]*CoordinateFailure, error) {
+	migrator := db.GetDB().WithContext(ctx).Migrator()
+	if !migrator.HasTable(&model.Address{}) {
+		return nil, nil
+	}
+
+	columns, err := migrator.ColumnTypes(&model.Address{})
+	if err != nil {
+		return nil, err
+	}
+	textual := false
+	for _, column := range columns {
+		name := column.Name()
+		if (name == "lat" || name == "long") && isText(column.DatabaseTypeName()) {
+			textual = true
+		}
+	}
+	if !textual {
+		return nil, nil
+	}
+
+	var failures []*CoordinateFailure
+	err = db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			ID   string
+			Lat  *string
+			Long *string
+		}
+		if err := tx.Raw("SELECT id, lat, long FROM addresses").Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			lat, long, err := geo.ParseCoordinates(deref(row.Lat), deref(row.Long))
+			if err != nil {
+				failures = append(failures, &CoordinateFailure{
+					ID:     row.ID,
+					Lat:    deref(row.Lat),
+					Long:   deref(row.Long),
+					Reason: err.Error(),
+				})
+			}
+
+			// Written back in a form the column type change can cast.
+			if err := tx.Exec(
+				"UPDATE addresses SET lat = ?, long = ? WHERE id = ?",
+				format(lat), format(long), row.ID,
+			).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec(`ALTER TABLE addresses
+			ALTER COLUMN lat TYPE double precision USING lat::double precision,
+			ALTER COLUMN long TYPE double precision USING long::double precision`).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return failures, nil
+}
+
+func isText(databaseType string) bool {
+	switch strings.ToLower(databaseType) {
+	case "text", "varchar", "character varying", "bpchar", "character":
+		return true
+	}
+	return false
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func format(f *float64) *string {
+	if f == nil {
+		return nil
+	}
+	s := strconv.FormatFloat(*f, 'g', -1, 64)
+	return &s
+}
@@ -2,51 +2,88 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/quangdangfit/gocommon/logger"
 	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
 
 	"main/internal/address/dto"
 	"main/internal/address/model"
 	"main/internal/address/repository"
+	userModel "main/internal/user/model"
+	"main/pkg/geocoder"
 	"main/pkg/paging"
 	"main/pkg/utils"
 )
 
+// ErrAddressNotFound is returned for addresses that do not exist or belong to
+// another user, so that callers cannot tell the two apart.
+var ErrAddressNotFound = errors.New("address not found")
+
+// ErrInvalidAddress wraps the validation errors of a request.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// Every method acts on the addresses of owner, the authenticated user, see
+// Owner. An empty owner lifts the scoping.
+//
 //go:generate mockery --name=IAddressService
 type IAddressService interface {
-	ListAddresses(c context.Context, req *dto.ListAddressReq) ([]*model.Address, *paging.Pagination, error)
-	GetAddressByID(ctx context.Context, id string) (*model.Address, error)
-	Create(ctx context.Context, req *dto.CreateAddressReq) (*model.Address, error)
-	Delete(ctx context.Context, id string, req *dto.DeleteAddressReq) (*model.Address, error)
-	Update(ctx context.Context, id string, req *dto.UpdateAddressReq) (*model.Address, error)
+	ListAddresses(c context.Context, owner string, req *dto.ListAddressReq) ([]*model.Address, *paging.Pagination, error)
+	ListNearby(ctx context.Context, owner string, req *dto.ListNearbyAddressReq) ([]*model.NearbyAddress, *paging.Pagination, error)
+	GetAddressByID(ctx context.Context, owner, id string) (*model.Address, error)
+	Create(ctx context.Context, owner string, req *dto.CreateAddressReq) (*model.Address, error)
+	Delete(ctx context.Context, owner, id string) (*model.Address, error)
+	Update(ctx context.Context, owner, id string, req *dto.UpdateAddressReq) (*model.Address, error)
+	GetDefault(ctx context.Context, userID string) (*model.Address, error)
+	SetDefault(ctx context.Context, owner, id string) (*model.Address, error)
+}
+
+// Owner returns the owner that scopes the calls of userID. Admins that
+// explicitly ask for all users get an empty owner, anyone else is scoped to
+// their own addresses.
+func Owner(userID, role string, all bool) string {
+	if all && role == string(userModel.UserRoleAdmin) {
+		return ""
+	}
+	return userID
 }
 
 type AddressService struct {
 	validator validation.Validation
 	repo      repository.IAddressRepository
+	geocoder  geocoder.Geocoder
 }
 
+// NewAddressService returns the address service. Addresses are not geocoded
+// when geocoder is nil.
 func NewAddressService(
 	validator validation.Validation,
 	repo repository.IAddressRepository,
+	geocoder geocoder.Geocoder,
 ) *AddressService {
 	return &AddressService{
 		validator: validator,
 		repo:      repo,
+		geocoder:  geocoder,
 	}
 }
 
-func (p *AddressService) GetAddressByID(ctx context.Context, id string) (*model.Address, error) {
-	Address, err := p.repo.GetAddressByID(ctx, id)
+func (p *AddressService) GetAddressByID(ctx context.Context, owner, id string) (*model.Address, error) {
+	Address, err := p.repo.GetAddressByID(ctx, owner, id)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 
 	return Address, nil
 }
 
-func (p *AddressService) ListAddresses(ctx context.Context, req *dto.ListAddressReq) ([]*model.Address, *paging.Pagination, error) {
+func (p *AddressService) ListAddresses(ctx context.Context, owner string, req *dto.ListAddressReq) ([]*model.Address, *paging.Pagination, error) {
+	if owner != "" {
+		req.IDUser = owner
+	}
+
 	Addresss, pagination, err := p.repo.ListAddresses(ctx, req)
 	if err != nil {
 		return nil, nil, err
@@ -55,13 +92,35 @@ func (p *AddressService) ListAddresses(ctx context.Context, req *dto.ListAddress
 	return Addresss, pagination, nil
 }
 
-func (p *AddressService) Create(ctx context.Context, req *dto.CreateAddressReq) (*model.Address, error) {
+// ListNearby finds the addresses of owner within req.RadiusKm of a point,
+// nearest first.
+func (p *AddressService) ListNearby(ctx context.Context, owner string, req *dto.ListNearbyAddressReq) ([]*model.NearbyAddress, *paging.Pagination, error) {
+	if owner != "" {
+		req.IDUser = owner
+	}
 	if err := p.validator.ValidateStruct(req); err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidAddress, err)
+	}
+
+	Addresses, pagination, err := p.repo.ListNearby(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return Addresses, pagination, nil
+}
+
+func (p *AddressService) Create(ctx context.Context, owner string, req *dto.CreateAddressReq) (*model.Address, error) {
+	if owner != "" {
+		req.IDUser = owner
+	}
+	if err := p.validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddress, err)
 	}
 
 	var Address model.Address
 	utils.Copy(&Address, req)
+	p.locate(ctx, &Address)
 
 	err := p.repo.Create(ctx, &Address)
 	if err != nil {
@@ -72,18 +131,19 @@ func (p *AddressService) Create(ctx context.Context, req *dto.CreateAddressReq)
 	return &Address, nil
 }
 
-func (p *AddressService) Update(ctx context.Context, id string, req *dto.UpdateAddressReq) (*model.Address, error) {
+func (p *AddressService) Update(ctx context.Context, owner, id string, req *dto.UpdateAddressReq) (*model.Address, error) {
 	if err := p.validator.ValidateStruct(req); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddress, err)
 	}
 
-	Address, err := p.repo.GetAddressByID(ctx, id)
+	Address, err := p.repo.GetAddressByID(ctx, owner, id)
 	if err != nil {
 		logger.Errorf("Update.GetAddressByID fail, id: %s, error: %s", id, err)
-		return nil, err
+		return nil, notFound(err)
 	}
 
 	utils.Copy(Address, req)
+	p.locate(ctx, Address)
 	err = p.repo.Update(ctx, Address)
 	if err != nil {
 		logger.Errorf("Update fail, id: %s, error: %s", id, err)
@@ -93,18 +153,13 @@ func (p *AddressService) Update(ctx context.Context, id string, req *dto.UpdateA
 	return Address, nil
 }
 
-func (p *AddressService) Delete(ctx context.Context, id string, req *dto.DeleteAddressReq) (*model.Address, error) {
-	if err := p.validator.ValidateStruct(req); err != nil {
-		return nil, err
-	}
-
-	Address, err := p.repo.GetAddressByID(ctx, id)
+func (p *AddressService) Delete(ctx context.Context, owner, id string) (*model.Address, error) {
+	Address, err := p.repo.GetAddressByID(ctx, owner, id)
 	if err != nil {
 		logger.Errorf("Delete.GetAddressByID fail, id: %s, error: %s", id, err)
-		return nil, err
+		return nil, notFound(err)
 	}
 
-	utils.Copy(Address, req)
 	err = p.repo.Delete(ctx, Address)
 	if err != nil {
 		logger.Errorf("Delete fail, id: %s, error: %s", id, err)
@@ -113,3 +168,64 @@ func (p *AddressService) Delete(ctx context.Context, id string, req *dto.DeleteA
 
 	return Address, nil
 }
+
+// GetDefault returns the default shipping address of userID.
+func (p *AddressService) GetDefault(ctx context.Context, userID string) (*model.Address, error) {
+	Address, err := p.repo.GetDefault(ctx, userID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return Address, nil
+}
+
+// SetDefault makes the address the default shipping address of its user.
+func (p *AddressService) SetDefault(ctx context.Context, owner, id string) (*model.Address, error) {
+	Address, err := p.repo.GetAddressByID(ctx, owner, id)
+	if err != nil {
+		logger.Errorf("SetDefault.GetAddressByID fail, id: %s, error: %s", id, err)
+		return nil, notFound(err)
+	}
+
+	err = p.repo.SetDefault(ctx, Address)
+	if err != nil {
+		logger.Errorf("SetDefault fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return Address, nil
+}
+
+// locate fills in the coordinates of an address from its city and street, or
+// its city and street from its coordinates. It is best effort, addresses the
+// geocoder cannot place are saved as sent.
+func (p *AddressService) locate(ctx context.Context, Address *model.Address) {
+	if p.geocoder == nil {
+		return
+	}
+
+	var location *geocoder.Location
+	var err error
+	switch {
+	case Address.Lat == nil && Address.City != "":
+		location, err = p.geocoder.Geocode(ctx, Address.City, Address.Street)
+		if err == nil {
+			Address.Lat, Address.Long = &location.Lat, &location.Long
+		}
+	case Address.Lat != nil && Address.City == "" && Address.Street == "":
+		location, err = p.geocoder.Reverse(ctx, *Address.Lat, *Address.Long)
+		if err == nil {
+			Address.City, Address.Street = location.City, location.Street
+		}
+	}
+	if err != nil && !errors.Is(err, geocoder.ErrNotFound) {
+		logger.Errorf("Geocoding fail, error: %s", err)
+	}
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAddressNotFound
+	}
+	return err
+}
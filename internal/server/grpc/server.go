@@ -12,6 +12,8 @@ import (
 	// cartGRPC "main/internal/cart/port/grpc"
 	addressGRPC "main/internal/address/port/grpc"
 	userGRPC "main/internal/user/port/grpc"
+	"main/pkg/apikey"
+	"main/pkg/audit"
 	"main/pkg/config"
 	"main/pkg/dbs"
 	"main/pkg/middleware"
@@ -27,11 +29,21 @@ type Server struct {
 }
 
 func NewServer(validator validation.Validation, db dbs.IDatabase, cache redis.IRedis) *Server {
-	interceptor := middleware.NewAuthInterceptor(config.AuthIgnoreMethods)
+	interceptor := middleware.NewAuthInterceptor(
+		config.AuthIgnoreMethods,
+		config.AuthRefreshMethods,
+		config.AuthMethodRoles,
+		config.AuthMethodScopes,
+		cache,
+		apikey.NewStore(db),
+	)
+
+	impersonation := middleware.NewImpersonationInterceptor(config.AuthReadOnlyMethods, audit.New(db))
 
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			interceptor.Unary(),
+			impersonation.Unary(),
 		),
 	)
 
@@ -45,7 +57,7 @@ func NewServer(validator validation.Validation, db dbs.IDatabase, cache redis.IR
 }
 
 func (s Server) Run() error {
-	userGRPC.RegisterHandlers(s.engine, s.db, s.validator)
+	userGRPC.RegisterHandlers(s.engine, s.db, s.validator, s.cache)
 	addressGRPC.RegisterHandlers(s.engine, s.db, s.validator, s.cache)
 	// cartGRPC.RegisterHandlers(s.engine, s.db, s.validator)
 
@@ -16,8 +16,11 @@ import (
 	// productHttp "main/internal/product/port/http"
 	addressHttp "main/internal/address/port/http"
 	userHttp "main/internal/user/port/http"
+	"main/pkg/audit"
 	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/jtoken"
+	"main/pkg/middleware"
 	"main/pkg/redis"
 	"main/pkg/response"
 )
@@ -75,8 +78,16 @@ func (s Server) GetEngine() *gin.Engine {
 }
 
 func (s Server) MapRoutes() error {
+	s.engine.Use(middleware.AuditImpersonation(audit.New(s.db)))
+
+	// Public keys for services that verify our tokens, served in the standard
+	// JWKS format rather than the response envelope.
+	s.engine.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, jtoken.PublicKeys())
+	})
+
 	v1 := s.engine.Group("/api/v1")
-	userHttp.Routes(v1, s.db, s.validator)
+	userHttp.Routes(v1, s.db, s.validator, s.cache)
 	addressHttp.Routes(v1, s.db, s.validator, s.cache)
 	// productHttp.Routes(v1, s.db, s.validator, s.cache)
 	// orderHttp.Routes(v1, s.db, s.validator)
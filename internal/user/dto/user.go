@@ -2,13 +2,32 @@ package dto
 
 import (
 	"time"
+
+	addressModel "main/internal/address/model"
+	"main/pkg/paging"
 )
 
 type User struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	Approve       bool      `json:"approve"`
+	DisplayName   string    `json:"display_name"`
+	Phone         string    `json:"phone"`
+	PhoneVerified bool      `json:"phone_verified"`
+	AvatarURL     string    `json:"avatar_url"`
+	Locale        string    `json:"locale"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// UpdateProfileReq only changes the fields that are present. An empty string
+// clears a field.
+type UpdateProfileReq struct {
+	DisplayName *string `json:"display_name" validate:"omitempty,max=100"`
+	Phone       *string `json:"phone" validate:"omitempty,len=0|e164"`
+	AvatarURL   *string `json:"avatar_url" validate:"omitempty,max=2048,len=0|http_url"`
+	Locale      *string `json:"locale" validate:"omitempty,max=35,len=0|bcp47_language_tag"`
 }
 
 type RegisterReq struct {
@@ -23,12 +42,50 @@ type RegisterRes struct {
 type LoginReq struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,password"`
+	// IP and UserAgent describe the client, filled in by the transport.
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 type LoginRes struct {
 	User         User   `json:"user"`
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	// MFARequired is set instead of the tokens when the user has 2FA enabled.
+	// MFAToken is then exchanged on /auth/login/mfa together with a code.
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
+
+type LoginMFAReq struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+	// IP and UserAgent describe the client, filled in by the transport.
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+type RequestOTPReq struct {
+	Phone string `json:"phone" validate:"required,e164"`
+	// IP is the client address, filled in by the transport.
+	IP string `json:"-"`
+}
+
+type VerifyOTPReq struct {
+	Phone string `json:"phone" validate:"required,e164"`
+	Code  string `json:"code" validate:"required"`
+	// IP and UserAgent describe the client, filled in by the transport.
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// OIDCLoginReq is what the OpenID Connect provider sends back to the callback.
+type OIDCLoginReq struct {
+	Code  string `json:"code" form:"code" validate:"required"`
+	State string `json:"state" form:"state" validate:"required"`
+	// IP and UserAgent describe the client, filled in by the transport.
+	IP        string `json:"-" form:"-"`
+	UserAgent string `json:"-" form:"-"`
 }
 
 type RefreshTokenReq struct {
@@ -36,7 +93,23 @@ type RefreshTokenReq struct {
 }
 
 type RefreshTokenRes struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Session is a device the user is logged in on. Current marks the session of
+// the token used for the request.
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
+
+type ListSessionsRes struct {
+	Sessions []*Session `json:"sessions"`
 }
 
 type ChangePasswordReq struct {
@@ -44,14 +117,201 @@ type ChangePasswordReq struct {
 	NewPassword string `json:"new_password" validate:"required,password"`
 }
 
+type DeleteAccountReq struct {
+	Password string `json:"password" validate:"required,password"`
+}
+
+type ExportAccountReq struct {
+	Format string `form:"format" validate:"omitempty,oneof=json zip"`
+}
+
+// AccountExport is the personal data held about a user.
+type AccountExport struct {
+	ExportedAt  time.Time               `json:"exported_at"`
+	User        User                    `json:"user"`
+	TOTPEnabled bool                    `json:"totp_enabled"`
+	Addresses   []*addressModel.Address `json:"addresses"`
+	Sessions    []*Session              `json:"sessions"`
+}
+
+type ExportFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+type ChangeEmailReq struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,password"`
+}
+
+type ConfirmEmailChangeReq struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type EnrollTOTPRes struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TOTPCodeReq carries a code from the authenticator app, or a recovery code
+// where those are accepted.
+type TOTPCodeReq struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type ConfirmTOTPRes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+//***************************************************************************\\
+//***************************************************************************\\
+
+// UserDetail is the admin view of a user account.
+type UserDetail struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Approve   bool      `json:"approve"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListUsersReq struct {
+	Email       string     `json:"email,omitempty" form:"email"`
+	Role        string     `json:"role,omitempty" form:"role" validate:"omitempty,oneof=admin customer"`
+	Approve     *bool      `json:"approve,omitempty" form:"approve"`
+	Disabled    *bool      `json:"disabled,omitempty" form:"disabled"`
+	CreatedFrom *time.Time `json:"created_from,omitempty" form:"created_from"`
+	CreatedTo   *time.Time `json:"created_to,omitempty" form:"created_to"`
+	Page        int64      `json:"-" form:"page"`
+	Limit       int64      `json:"-" form:"limit"`
+}
+
+type ListUsersRes struct {
+	Users      []*UserDetail      `json:"users"`
+	Pagination *paging.Pagination `json:"pagination"`
+}
+
+type UpdateRoleReq struct {
+	Role string `json:"role" validate:"required,oneof=admin customer"`
+}
+
+type SetDisabledReq struct {
+	Disabled bool `json:"disabled"`
+}
+
+// UnlockUserReq optionally names a client IP whose login lockout is lifted
+// together with the one of the user.
+type UnlockUserReq struct {
+	IP string `json:"ip" form:"ip" validate:"omitempty,ip"`
+}
+
+type ListAuditEventsReq struct {
+	UserID    string     `json:"user_id,omitempty" form:"user_id"`
+	ActorID   string     `json:"actor_id,omitempty" form:"actor_id"`
+	Email     string     `json:"email,omitempty" form:"email"`
+	Type      string     `json:"type,omitempty" form:"type" validate:"omitempty,oneof=login login_otp login_mfa login_oidc mfa_challenge register verify_email password_change password_reset token_refresh logout impersonation_start impersonated_request"`
+	Outcome   string     `json:"outcome,omitempty" form:"outcome" validate:"omitempty,oneof=success failure"`
+	Transport string     `json:"transport,omitempty" form:"transport" validate:"omitempty,oneof=http grpc"`
+	IP        string     `json:"ip,omitempty" form:"ip"`
+	From      *time.Time `json:"from,omitempty" form:"from"`
+	To        *time.Time `json:"to,omitempty" form:"to"`
+	Page      int64      `json:"-" form:"page"`
+	Limit     int64      `json:"-" form:"limit"`
+}
+
+type AuditEvent struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Type      string    `json:"type"`
+	Outcome   string    `json:"outcome"`
+	Reason    string    `json:"reason"`
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Transport string    `json:"transport"`
+	ActorID   string    `json:"actor_id"`
+	Resource  string    `json:"resource"`
+}
+
+type ListAuditEventsRes struct {
+	Events     []*AuditEvent      `json:"events"`
+	Pagination *paging.Pagination `json:"pagination"`
+}
+
+// ImpersonateReq starts an impersonation. ReadOnly limits it to requests that
+// change nothing, Reason is kept in the audit trail.
+type ImpersonateReq struct {
+	ReadOnly bool   `json:"read_only"`
+	Reason   string `json:"reason" validate:"max=500"`
+}
+
+type ImpersonateRes struct {
+	User        User      `json:"user"`
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ReadOnly    bool      `json:"read_only"`
+}
+
+// CreateAPIKeyReq creates a key acting as UserID, the admin creating it when
+// empty, limited to Scopes. Keys without ExpiresAt never expire.
+type CreateAPIKeyReq struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	UserID    string     `json:"user_id,omitempty"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=users:read users:write addresses:read addresses:write audit:read"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Hint       string     `json:"hint"`
+	UserID     string     `json:"user_id"`
+	Role       string     `json:"role"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// CreateAPIKeyRes holds the only copy of the key that is ever returned.
+type CreateAPIKeyRes struct {
+	APIKey *APIKey `json:"api_key"`
+	Key    string  `json:"key"`
+}
+
+type ListAPIKeysRes struct {
+	APIKeys []*APIKey `json:"api_keys"`
+}
+
 //***************************************************************************\\
 //***************************************************************************\\
 
 type VerifyRequest struct {
-	Email      string `json:"email"`
-	VerifyCode string `json:"verify_code"`
+	Email      string `json:"email" validate:"required,email"`
+	VerifyCode string `json:"verify_code" validate:"required"`
 }
 
 type VerifyResponse struct {
 	Message string `json:"message"`
 }
+
+type ResendVerifyCodeReq struct {
+	Email string `json:"email" validate:"required,email"`
+	// IP is the client address, filled in by the transport.
+	IP string `json:"-"`
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordReq struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,password"`
+}
@@ -66,7 +66,7 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
-	"main/pkg/utils"
+	"main/pkg/password"
 )
 
 // UserRole represents the role of a user
@@ -80,15 +80,45 @@ const (
 
 // User represents a user in the system
 type User struct {
-	ID         string     `json:"id" gorm:"unique;not null;index;primary_key"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
-	DeletedAt  *time.Time `json:"deleted_at" gorm:"index"`
-	Email      string     `json:"email" gorm:"unique;not null;index:idx_user_email"`
-	Password   string     `json:"password"`
-	Role       UserRole   `json:"role"`
-	VerifyCode string     `json:"verify_code"`
-	Approve    bool       `json:"approve"`
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt makes deletes soft, deleted users are left out of every query
+	// until they are purged after the grace period.
+	DeletedAt  gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	Email      string         `json:"email" gorm:"unique;not null;index:idx_user_email"`
+	Password   string         `json:"password"`
+	Role       UserRole       `json:"role"`
+	VerifyCode string         `json:"verify_code"`
+	Approve    bool           `json:"approve"`
+	Disabled   bool           `json:"disabled"`
+
+	DisplayName string `json:"display_name"`
+	// Phone is unique so that it can be used to log in with a code sent by
+	// SMS. PhoneVerified is set by the first such login.
+	Phone         string `json:"phone" gorm:"uniqueIndex:idx_user_phone,where:phone <> ''"`
+	PhoneVerified bool   `json:"phone_verified"`
+	AvatarURL     string `json:"avatar_url"`
+	Locale        string `json:"locale"`
+
+	// TOTPSecret is set on enrollment and only used once TOTPEnabled is set by
+	// a confirmed code. TOTPLastStep keeps a code from being used twice.
+	TOTPSecret         string `json:"-"`
+	TOTPEnabled        bool   `json:"totp_enabled"`
+	TOTPLastStep       int64  `json:"-"`
+	RecoveryCodeHashes string `json:"-"`
+
+	VerifyCodeExpiresAt *time.Time `json:"verify_code_expires_at"`
+	VerifyAttempts      int        `json:"verify_attempts"`
+
+	ResetTokenHash      string     `json:"reset_token_hash" gorm:"index"`
+	ResetTokenExpiresAt *time.Time `json:"reset_token_expires_at"`
+
+	// PendingEmail replaces Email once the code sent to it is confirmed.
+	PendingEmail         string     `json:"pending_email"`
+	EmailChangeCodeHash  string     `json:"-"`
+	EmailChangeExpiresAt *time.Time `json:"-"`
+	EmailChangeAttempts  int        `json:"-"`
 }
 
 // BeforeCreate is a hook that is called before creating a new user
@@ -97,7 +127,11 @@ func (user *User) BeforeCreate(tx *gorm.DB) error {
 	user.ID = uuid.New().String()
 
 	// Hash and salt the password for security
-	user.Password = utils.HashAndSalt([]byte(user.Password))
+	hash, err := password.Hash(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
 
 	// Set the default role to customer if not specified
 	if user.Role == "" {
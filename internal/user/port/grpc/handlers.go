@@ -3,11 +3,25 @@ package grpc
 import (
 	"context"
 	"errors"
+	"net"
+	"strconv"
+	"time"
 
 	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 
 	"main/internal/user/dto"
 	"main/internal/user/service"
+	"main/pkg/apikey"
+	"main/pkg/jtoken"
+	"main/pkg/middleware"
+	"main/pkg/oidc"
+	"main/pkg/otp"
+	"main/pkg/throttle"
 	"main/pkg/utils"
 	pb "main/proto/gen/go/user"
 )
@@ -25,19 +39,91 @@ func NewUserHandler(service service.IUserService) *UserHandler {
 }
 
 func (h *UserHandler) Login(ctx context.Context, req *pb.LoginReq) (*pb.LoginRes, error) {
-	user, accessToken, refreshToken, err := h.service.Login(ctx, &dto.LoginReq{
-		Email:    req.Email,
-		Password: req.Password,
+	result, err := h.service.Login(ctx, &dto.LoginReq{
+		Email:     req.Email,
+		Password:  req.Password,
+		IP:        clientIP(ctx),
+		UserAgent: clientUserAgent(ctx),
 	})
 	if err != nil {
-		logger.Error("Failed to register ", err)
-		return nil, err
+		return nil, loginError(err)
 	}
 
 	var res pb.LoginRes
-	utils.Copy(&res.User, &user)
-	res.AccessToken = accessToken
-	res.RefreshToken = refreshToken
+	utils.Copy(&res, result)
+	return &res, nil
+}
+
+func (h *UserHandler) LoginMFA(ctx context.Context, req *pb.LoginMFAReq) (*pb.LoginMFARes, error) {
+	result, err := h.service.LoginMFA(ctx, &dto.LoginMFAReq{
+		MFAToken:  req.MfaToken,
+		Code:      req.Code,
+		IP:        clientIP(ctx),
+		UserAgent: clientUserAgent(ctx),
+	})
+	if err != nil {
+		return nil, loginError(err)
+	}
+
+	var res pb.LoginMFARes
+	utils.Copy(&res, result)
+	return &res, nil
+}
+
+func (h *UserHandler) RequestOTP(ctx context.Context, req *pb.RequestOTPReq) (*pb.RequestOTPRes, error) {
+	err := h.service.RequestOTP(ctx, &dto.RequestOTPReq{
+		Phone: req.Phone,
+		IP:    clientIP(ctx),
+	})
+	if errors.Is(err, throttle.ErrLocked) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		logger.Error("Failed to send login code ", err)
+		return nil, err
+	}
+
+	return &pb.RequestOTPRes{}, nil
+}
+
+func (h *UserHandler) VerifyOTP(ctx context.Context, req *pb.VerifyOTPReq) (*pb.VerifyOTPRes, error) {
+	result, err := h.service.VerifyOTP(ctx, &dto.VerifyOTPReq{
+		Phone:     req.Phone,
+		Code:      req.Code,
+		IP:        clientIP(ctx),
+		UserAgent: clientUserAgent(ctx),
+	})
+	if err != nil {
+		return nil, loginError(err)
+	}
+
+	var res pb.VerifyOTPRes
+	utils.Copy(&res, result)
+	return &res, nil
+}
+
+func (h *UserHandler) OIDCAuthorize(ctx context.Context, req *pb.OIDCAuthorizeReq) (*pb.OIDCAuthorizeRes, error) {
+	url, err := h.service.OIDCAuthURL(ctx)
+	if err != nil {
+		return nil, oidcError(err)
+	}
+
+	return &pb.OIDCAuthorizeRes{Url: url}, nil
+}
+
+func (h *UserHandler) OIDCLogin(ctx context.Context, req *pb.OIDCLoginReq) (*pb.OIDCLoginRes, error) {
+	result, err := h.service.OIDCLogin(ctx, &dto.OIDCLoginReq{
+		Code:      req.Code,
+		State:     req.State,
+		IP:        clientIP(ctx),
+		UserAgent: clientUserAgent(ctx),
+	})
+	if err != nil {
+		return nil, oidcError(err)
+	}
+
+	var res pb.OIDCLoginRes
+	utils.Copy(&res, result)
 	return &res, nil
 }
 
@@ -57,12 +143,12 @@ func (h *UserHandler) Register(ctx context.Context, req *pb.RegisterReq) (*pb.Re
 }
 
 func (h *UserHandler) GetMe(ctx context.Context, _ *pb.GetMeReq) (*pb.GetMeRes, error) {
-	userID, _ := ctx.Value("userId").(string)
-	if userID == "" {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
 		return nil, errors.New("unauthorized")
 	}
 
-	user, err := h.service.GetUserByID(ctx, userID)
+	user, err := h.service.GetUserByID(ctx, principal.UserID)
 	if err != nil {
 		logger.Error("Failed to register ", err)
 		return nil, err
@@ -73,31 +159,139 @@ func (h *UserHandler) GetMe(ctx context.Context, _ *pb.GetMeReq) (*pb.GetMeRes,
 	return &res, nil
 }
 
-func (h *UserHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenReq) (*pb.RefreshTokenRes, error) {
-	userID, _ := ctx.Value("userId").(string)
-	if userID == "" {
+func (h *UserHandler) UpdateProfile(ctx context.Context, req *pb.UpdateProfileReq) (*pb.UpdateProfileRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
 		return nil, errors.New("unauthorized")
 	}
 
-	accessToken, err := h.service.RefreshToken(ctx, userID)
+	fields := map[string]string{
+		"display_name": req.DisplayName,
+		"phone":        req.Phone,
+		"avatar_url":   req.AvatarUrl,
+		"locale":       req.Locale,
+	}
+	set := make(map[string]*string)
+	if len(req.UpdateFields) == 0 {
+		for name, value := range fields {
+			if value != "" {
+				value := value
+				set[name] = &value
+			}
+		}
+	}
+	for _, name := range req.UpdateFields {
+		value, ok := fields[name]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown profile field %q", name)
+		}
+		set[name] = &value
+	}
+
+	user, err := h.service.UpdateProfile(ctx, principal.UserID, &dto.UpdateProfileReq{
+		DisplayName: set["display_name"],
+		Phone:       set["phone"],
+		AvatarURL:   set["avatar_url"],
+		Locale:      set["locale"],
+	})
+	if errors.Is(err, service.ErrPhoneTaken) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
 	if err != nil {
-		logger.Error("Failed to register ", err)
+		logger.Error("Failed to update profile ", err)
+		return nil, err
+	}
+
+	var res pb.UpdateProfileRes
+	utils.Copy(&res.User, &user)
+	return &res, nil
+}
+
+func (h *UserHandler) ExportAccount(ctx context.Context, req *pb.ExportAccountReq) (*pb.ExportAccountRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	file, err := h.service.ExportAccount(ctx, principal.UserID, &dto.ExportAccountReq{
+		Format: req.Format,
+	})
+	if err != nil {
+		logger.Error("Failed to export account ", err)
+		return nil, err
+	}
+
+	return &pb.ExportAccountRes{
+		Filename:    file.Name,
+		ContentType: file.ContentType,
+		Data:        file.Data,
+	}, nil
+}
+
+func (h *UserHandler) DeleteAccount(ctx context.Context, req *pb.DeleteAccountReq) (*pb.DeleteAccountRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	err := h.service.DeleteAccount(ctx, principal.UserID, &dto.DeleteAccountReq{
+		Password: req.Password,
+	})
+	if errors.Is(err, service.ErrWrongPassword) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		logger.Error("Failed to delete account ", err)
+		return nil, err
+	}
+
+	return &pb.DeleteAccountRes{}, nil
+}
+
+func (h *UserHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenReq) (*pb.RefreshTokenRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok || principal.TokenType != jtoken.RefreshTokenType {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	accessToken, refreshToken, err := h.service.RefreshToken(ctx, principal.UserID, principal.SessionID, principal.TokenID)
+	if errors.Is(err, jtoken.ErrSessionRevoked) || errors.Is(err, jtoken.ErrTokenReused) ||
+		errors.Is(err, service.ErrUserDisabled) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		logger.Error("Failed to refresh token ", err)
 		return nil, err
 	}
 
 	res := pb.RefreshTokenRes{
-		AccessToken: accessToken,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}
 	return &res, nil
 }
 
+func (h *UserHandler) Logout(ctx context.Context, _ *pb.LogoutReq) (*pb.LogoutRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	if err := h.service.Logout(ctx, principal.SessionOwner(), principal.SessionID); err != nil {
+		logger.Error("Failed to logout ", err)
+		return nil, err
+	}
+
+	return &pb.LogoutRes{}, nil
+}
+
 func (h *UserHandler) ChangePassword(ctx context.Context, req *pb.ChangePasswordReq) (*pb.ChangePasswordRes, error) {
-	userID, _ := ctx.Value("userId").(string)
-	if userID == "" {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
 		return nil, errors.New("unauthorized")
 	}
 
-	err := h.service.ChangePassword(ctx, userID, &dto.ChangePasswordReq{
+	err := h.service.ChangePassword(ctx, principal.UserID, &dto.ChangePasswordReq{
 		Password:    req.Password,
 		NewPassword: req.NewPassword,
 	})
@@ -109,17 +303,498 @@ func (h *UserHandler) ChangePassword(ctx context.Context, req *pb.ChangePassword
 	return &pb.ChangePasswordRes{}, nil
 }
 
-func (h *UserHandler) VerfiyCode(ctx context.Context, req *pb.VerifyRequest) (*pb.VerifyResponse, error) {
+func (h *UserHandler) ForgotPassword(ctx context.Context, req *pb.ForgotPasswordReq) (*pb.ForgotPasswordRes, error) {
+	err := h.service.ForgotPassword(ctx, &dto.ForgotPasswordReq{
+		Email: req.Email,
+	})
+	if err != nil {
+		logger.Error("Failed to send reset token ", err)
+		return nil, err
+	}
+
+	return &pb.ForgotPasswordRes{}, nil
+}
 
-	_, err := h.service.VerifyUser(ctx, dto.VerifyRequest{
+func (h *UserHandler) ResetPassword(ctx context.Context, req *pb.ResetPasswordReq) (*pb.ResetPasswordRes, error) {
+	err := h.service.ResetPassword(ctx, &dto.ResetPasswordReq{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	})
+	if errors.Is(err, service.ErrInvalidResetToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		logger.Error("Failed to reset password ", err)
+		return nil, err
+	}
+
+	return &pb.ResetPasswordRes{}, nil
+}
+
+func (h *UserHandler) VerifyUser(ctx context.Context, req *pb.VerifyRequest) (*pb.VerifyResponse, error) {
+	res, err := h.service.VerifyUser(ctx, dto.VerifyRequest{
 		Email:      req.Email,
-		VerifyCode: req.VerifyCode})
+		VerifyCode: req.VerifyCode,
+	})
+	if err != nil {
+		logger.Error("Failed to verify user ", err)
+		return nil, err
+	}
 
+	return &pb.VerifyResponse{Message: res.Message}, nil
+}
+
+func (h *UserHandler) ResendVerifyCode(ctx context.Context, req *pb.ResendVerifyCodeReq) (*pb.ResendVerifyCodeRes, error) {
+	err := h.service.ResendVerifyCode(ctx, &dto.ResendVerifyCodeReq{
+		Email: req.Email,
+		IP:    clientIP(ctx),
+	})
+	if errors.Is(err, throttle.ErrLocked) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 	if err != nil {
-		logger.Error("Failed to register ", err)
+		logger.Error("Failed to resend verify code ", err)
+		return nil, err
+	}
+
+	return &pb.ResendVerifyCodeRes{}, nil
+}
+
+func (h *UserHandler) ListUsers(ctx context.Context, req *pb.ListUsersReq) (*pb.ListUsersRes, error) {
+	listReq := dto.ListUsersReq{
+		Email: req.Email,
+		Role:  req.Role,
+		Page:  req.Page,
+		Limit: req.Limit,
+	}
+
+	var err error
+	if listReq.Approve, err = parseBoolFilter(req.Approve); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid approve filter")
+	}
+	if listReq.Disabled, err = parseBoolFilter(req.Disabled); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid disabled filter")
+	}
+	if listReq.CreatedFrom, err = parseTimeFilter(req.CreatedFrom); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid created_from filter")
+	}
+	if listReq.CreatedTo, err = parseTimeFilter(req.CreatedTo); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid created_to filter")
+	}
+
+	users, pagination, err := h.service.ListUsers(ctx, &listReq)
+	if err != nil {
+		logger.Error("Failed to get list users ", err)
 		return nil, err
+	}
 
+	var res pb.ListUsersRes
+	utils.Copy(&res.Users, &users)
+	utils.Copy(&res.Pagination, &pagination)
+	return &res, nil
+}
+
+func (h *UserHandler) UpdateUserRole(ctx context.Context, req *pb.UpdateUserRoleReq) (*pb.UpdateUserRoleRes, error) {
+	user, err := h.service.UpdateRole(ctx, req.Id, &dto.UpdateRoleReq{
+		Role: req.Role,
+	})
+	if err != nil {
+		return nil, adminError(err)
 	}
 
-	return &pb.VerifyResponse{}, nil
+	var res pb.UpdateUserRoleRes
+	utils.Copy(&res.User, &user)
+	return &res, nil
+}
+
+func (h *UserHandler) ApproveUser(ctx context.Context, req *pb.ApproveUserReq) (*pb.ApproveUserRes, error) {
+	user, err := h.service.ApproveUser(ctx, req.Id)
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	var res pb.ApproveUserRes
+	utils.Copy(&res.User, &user)
+	return &res, nil
+}
+
+func (h *UserHandler) SetUserDisabled(ctx context.Context, req *pb.SetUserDisabledReq) (*pb.SetUserDisabledRes, error) {
+	user, err := h.service.SetDisabled(ctx, req.Id, req.Disabled)
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	var res pb.SetUserDisabledRes
+	utils.Copy(&res.User, &user)
+	return &res, nil
+}
+
+func (h *UserHandler) UnlockUser(ctx context.Context, req *pb.UnlockUserReq) (*pb.UnlockUserRes, error) {
+	if err := h.service.UnlockUser(ctx, req.Id, &dto.UnlockUserReq{IP: req.Ip}); err != nil {
+		return nil, adminError(err)
+	}
+
+	return &pb.UnlockUserRes{}, nil
+}
+
+func (h *UserHandler) ChangeEmail(ctx context.Context, req *pb.ChangeEmailReq) (*pb.ChangeEmailRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	err := h.service.ChangeEmail(ctx, principal.UserID, &dto.ChangeEmailReq{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		return nil, emailChangeError(err)
+	}
+
+	return &pb.ChangeEmailRes{}, nil
+}
+
+func (h *UserHandler) ConfirmEmailChange(ctx context.Context, req *pb.ConfirmEmailChangeReq) (*pb.ConfirmEmailChangeRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	user, err := h.service.ConfirmEmailChange(ctx, principal.UserID, &dto.ConfirmEmailChangeReq{
+		Code: req.Code,
+	})
+	if err != nil {
+		return nil, emailChangeError(err)
+	}
+
+	var res pb.ConfirmEmailChangeRes
+	utils.Copy(&res.User, &user)
+	return &res, nil
+}
+
+func (h *UserHandler) EnrollTOTP(ctx context.Context, _ *pb.EnrollTOTPReq) (*pb.EnrollTOTPRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	result, err := h.service.EnrollTOTP(ctx, principal.UserID)
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &pb.EnrollTOTPRes{Secret: result.Secret, Uri: result.URI}, nil
+}
+
+func (h *UserHandler) ConfirmTOTP(ctx context.Context, req *pb.ConfirmTOTPReq) (*pb.ConfirmTOTPRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	result, err := h.service.ConfirmTOTP(ctx, principal.UserID, &dto.TOTPCodeReq{
+		Code: req.Code,
+	})
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &pb.ConfirmTOTPRes{RecoveryCodes: result.RecoveryCodes}, nil
+}
+
+func (h *UserHandler) DisableTOTP(ctx context.Context, req *pb.DisableTOTPReq) (*pb.DisableTOTPRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	err := h.service.DisableTOTP(ctx, principal.UserID, &dto.TOTPCodeReq{
+		Code: req.Code,
+	})
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &pb.DisableTOTPRes{}, nil
+}
+
+func (h *UserHandler) ListSessions(ctx context.Context, _ *pb.ListSessionsReq) (*pb.ListSessionsRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	sessions, err := h.service.ListSessions(ctx, principal.UserID, principal.SessionID)
+	if err != nil {
+		logger.Error("Failed to list sessions ", err)
+		return nil, err
+	}
+
+	var res pb.ListSessionsRes
+	utils.Copy(&res.Sessions, &sessions)
+	return &res, nil
+}
+
+func (h *UserHandler) RevokeSession(ctx context.Context, req *pb.RevokeSessionReq) (*pb.RevokeSessionRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	err := h.service.RevokeSession(ctx, principal.UserID, req.Id)
+	if errors.Is(err, service.ErrSessionNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		logger.Error("Failed to revoke session ", err)
+		return nil, err
+	}
+
+	return &pb.RevokeSessionRes{}, nil
+}
+
+func (h *UserHandler) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsReq) (*pb.ListAuditEventsRes, error) {
+	listReq := dto.ListAuditEventsReq{
+		UserID:    req.UserId,
+		ActorID:   req.ActorId,
+		Email:     req.Email,
+		Type:      req.Type,
+		Outcome:   req.Outcome,
+		Transport: req.Transport,
+		IP:        req.Ip,
+		Page:      req.Page,
+		Limit:     req.Limit,
+	}
+
+	var err error
+	if listReq.From, err = parseTimeFilter(req.From); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid from filter")
+	}
+	if listReq.To, err = parseTimeFilter(req.To); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid to filter")
+	}
+
+	events, pagination, err := h.service.ListAuditEvents(ctx, &listReq)
+	if err != nil {
+		logger.Error("Failed to list audit events ", err)
+		return nil, err
+	}
+
+	var res pb.ListAuditEventsRes
+	utils.Copy(&res.Events, &events)
+	utils.Copy(&res.Pagination, &pagination)
+	return &res, nil
+}
+
+func (h *UserHandler) ImpersonateUser(ctx context.Context, req *pb.ImpersonateUserReq) (*pb.ImpersonateUserRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	result, err := h.service.Impersonate(ctx, principal.UserID, principal.SessionID, req.Id, &dto.ImpersonateReq{
+		ReadOnly: req.ReadOnly,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		return nil, impersonationError(err)
+	}
+
+	var res pb.ImpersonateUserRes
+	utils.Copy(&res, result)
+	return &res, nil
+}
+
+func (h *UserHandler) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyReq) (*pb.CreateAPIKeyRes, error) {
+	principal, ok := middleware.GetPrincipal(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	create := dto.CreateAPIKeyReq{
+		Name:   req.Name,
+		UserID: req.UserId,
+		Scopes: req.Scopes,
+	}
+	if req.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid expires_at")
+		}
+		create.ExpiresAt = &expiresAt
+	}
+
+	result, err := h.service.CreateAPIKey(ctx, principal.UserID, &create)
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+
+	var res pb.CreateAPIKeyRes
+	utils.Copy(&res, result)
+	return &res, nil
+}
+
+func (h *UserHandler) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysReq) (*pb.ListAPIKeysRes, error) {
+	keys, err := h.service.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+
+	var res pb.ListAPIKeysRes
+	utils.Copy(&res.ApiKeys, &keys)
+	return &res, nil
+}
+
+func (h *UserHandler) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyReq) (*pb.RevokeAPIKeyRes, error) {
+	if err := h.service.RevokeAPIKey(ctx, req.Id); err != nil {
+		return nil, apiKeyError(err)
+	}
+
+	return &pb.RevokeAPIKeyRes{}, nil
+}
+
+// loginError maps a failed Login or LoginMFA to a gRPC status.
+func loginError(err error) error {
+	switch {
+	case errors.Is(err, throttle.ErrLocked):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, service.ErrEmailNotVerified), errors.Is(err, service.ErrUserDisabled):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrInvalidMFAToken), errors.Is(err, service.ErrWrongMFACode),
+		errors.Is(err, otp.ErrInvalidCode):
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		logger.Error("Failed to login ", err)
+		return err
+	}
+}
+
+func oidcError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrOIDCDisabled):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, oidc.ErrInvalidState):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, oidc.ErrExchangeFailed), errors.Is(err, oidc.ErrInvalidIDToken):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, oidc.ErrUnverifiedEmail):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return loginError(err)
+	}
+}
+
+func emailChangeError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrEmailTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, service.ErrWrongPassword),
+		errors.Is(err, service.ErrSameEmail):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrNoPendingEmail),
+		errors.Is(err, service.ErrWrongVerifyCode),
+		errors.Is(err, service.ErrVerifyCodeExpired),
+		errors.Is(err, service.ErrTooManyAttempts):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		logger.Error("Failed to change email ", err)
+		return err
+	}
+}
+
+func mfaError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrWrongMFACode),
+		errors.Is(err, service.ErrMFAAlreadyEnabled),
+		errors.Is(err, service.ErrMFANotEnrolled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		logger.Error("Failed to update two-factor authentication ", err)
+		return err
+	}
+}
+
+func adminError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	logger.Error("Failed to update user ", err)
+	return err
+}
+
+func impersonationError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrCannotImpersonate):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrUserDisabled):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		logger.Error("Failed to impersonate ", err)
+		return err
+	}
+}
+
+func apiKeyError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, apikey.ErrKeyNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrUserDisabled), errors.Is(err, service.ErrInvalidExpiry):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		logger.Error("Failed to manage api key ", err)
+		return err
+	}
+}
+
+// clientIP returns the address of the caller without the port.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func clientUserAgent(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get("user-agent"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func parseBoolFilter(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func parseTimeFilter(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
 }
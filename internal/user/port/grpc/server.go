@@ -4,15 +4,15 @@ import (
 	"github.com/quangdangfit/gocommon/validation"
 	"google.golang.org/grpc"
 
-	"main/internal/user/repository"
 	"main/internal/user/service"
+	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/redis"
 	pb "main/proto/gen/go/user"
 )
 
-func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation) {
-	userRepo := repository.NewUserRepository(db)
-	userSvc := service.NewUserService(validator, userRepo)
+func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation, cache redis.IRedis) {
+	userSvc := service.NewFromConfig(config.GetConfig(), db, cache, validator)
 	userHandler := NewUserHandler(userSvc)
 
 	pb.RegisterUserServiceServer(svr, userHandler)
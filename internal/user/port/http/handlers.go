@@ -2,14 +2,24 @@ package http
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/quangdangfit/gocommon/logger"
+	"gorm.io/gorm"
 
 	"main/internal/user/dto"
 	"main/internal/user/service"
+	"main/pkg/apikey"
+	"main/pkg/jtoken"
+	"main/pkg/middleware"
+	"main/pkg/oidc"
+	"main/pkg/otp"
 	"main/pkg/response"
+	"main/pkg/throttle"
 	"main/pkg/utils"
 )
 
@@ -39,18 +49,145 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, accessToken, refreshToken, err := h.service.Login(c, &req)
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	res, err := h.service.Login(c, &req)
 	if err != nil {
-		logger.Error("Failed to login ", err)
+		loginError(c, err)
+		return
+	}
+	writeLogin(c, res)
+}
+
+// LoginMFA godoc
+//
+//	@Summary	Finish a login with a TOTP or recovery code
+//	@Tags		users
+//	@Produce	json
+//	@Param		_	body		dto.LoginMFAReq	true	"Body"
+//	@Success	200	{object}	dto.LoginRes
+//	@Router		/auth/login/mfa [post]
+func (h *UserHandler) LoginMFA(c *gin.Context) {
+	var req dto.LoginMFAReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	res, err := h.service.LoginMFA(c, &req)
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+	writeLogin(c, res)
+}
+
+// RequestOTP godoc
+//
+//	@Summary	Send a login code to a phone
+//	@Tags		users
+//	@Produce	json
+//	@Param		_	body	dto.RequestOTPReq	true	"Body"
+//	@Router		/auth/otp/request [post]
+func (h *UserHandler) RequestOTP(c *gin.Context) {
+	var req dto.RequestOTPReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.IP = c.ClientIP()
+	err := h.service.RequestOTP(c, &req)
+	var locked *throttle.LockedError
+	if errors.As(err, &locked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		response.Error(c, http.StatusTooManyRequests, err, "Too many codes requested, try again later")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to send login code ", err)
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
 		return
 	}
+	response.JSON(c, http.StatusOK, nil)
+}
 
-	var res dto.LoginRes
-	utils.Copy(&res.User, &user)
-	res.AccessToken = accessToken
-	res.RefreshToken = refreshToken
-	response.JSON(c, http.StatusOK, res)
+// VerifyOTP godoc
+//
+//	@Summary	Log in with a code sent to a phone
+//	@Tags		users
+//	@Produce	json
+//	@Param		_	body		dto.VerifyOTPReq	true	"Body"
+//	@Success	200	{object}	dto.LoginRes
+//	@Router		/auth/otp/verify [post]
+func (h *UserHandler) VerifyOTP(c *gin.Context) {
+	var req dto.VerifyOTPReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	res, err := h.service.VerifyOTP(c, &req)
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+	writeLogin(c, res)
+}
+
+// OIDCLogin godoc
+//
+//	@Summary	Log in with the OpenID Connect provider
+//	@Tags		users
+//	@Success	302
+//	@Router		/auth/oidc/login [get]
+func (h *UserHandler) OIDCLogin(c *gin.Context) {
+	url, err := h.service.OIDCAuthURL(c)
+	if err != nil {
+		oidcError(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, url)
+}
+
+// OIDCCallback godoc
+//
+//	@Summary	Finish a login with the OpenID Connect provider
+//	@Tags		users
+//	@Produce	json
+//	@Param		code	query		string	true	"Authorization code"
+//	@Param		state	query		string	true	"State of the login"
+//	@Success	200		{object}	dto.LoginRes
+//	@Router		/auth/oidc/callback [get]
+func (h *UserHandler) OIDCCallback(c *gin.Context) {
+	// The provider reports a refused or cancelled login in the query.
+	if reason := c.Query("error"); reason != "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(reason), "Login was refused by the provider")
+		return
+	}
+
+	var req dto.OIDCLoginReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to parse request query: ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	res, err := h.service.OIDCLogin(c, &req)
+	if err != nil {
+		oidcError(c, err)
+		return
+	}
+	writeLogin(c, res)
 }
 
 // Register godoc
@@ -90,13 +227,13 @@ func (h *UserHandler) Register(c *gin.Context) {
 //	@Success	200	{object}	dto.User
 //	@Router		/auth/me [get]
 func (h *UserHandler) GetMe(c *gin.Context) {
-	userID := c.GetString("userId")
-	if userID == "" {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
 		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
 		return
 	}
 
-	user, err := h.service.GetUserByID(c, userID)
+	user, err := h.service.GetUserByID(c, principal.UserID)
 	if err != nil {
 		logger.Error(err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -108,35 +245,184 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 	response.JSON(c, http.StatusOK, res)
 }
 
-// GetMe godoc
+// UpdateProfile godoc
 //
-//	@Summary	get my profile
+//	@Summary	update my profile
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.UpdateProfileReq	true	"Body"
+//	@Success	200	{object}	dto.User
+//	@Router		/auth/me [patch]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	var req dto.UpdateProfileReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	user, err := h.service.UpdateProfile(c, principal.UserID, &req)
+	if errors.Is(err, service.ErrPhoneTaken) {
+		response.Error(c, http.StatusConflict, err, "Phone already in use")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to update profile ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.User
+	utils.Copy(&res, &user)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ExportAccount godoc
+//
+//	@Summary	Download my personal data
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json,application/zip
+//	@Param		format	query	string	false	"json or zip"
+//	@Router		/auth/me/export [get]
+func (h *UserHandler) ExportAccount(c *gin.Context) {
+	var req dto.ExportAccountReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to parse request query: ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	file, err := h.service.ExportAccount(c, principal.UserID, &req)
+	if err != nil {
+		logger.Error("Failed to export account ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// DeleteAccount godoc
+//
+//	@Summary	Delete my account
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body	dto.DeleteAccountReq	true	"Body"
+//	@Router		/auth/me [delete]
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	var req dto.DeleteAccountReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	err := h.service.DeleteAccount(c, principal.UserID, &req)
+	if errors.Is(err, service.ErrWrongPassword) {
+		response.Error(c, http.StatusBadRequest, err, "Wrong password")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to delete account ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// RefreshToken godoc
+//
+//	@Summary	rotate the refresh token and issue a new token pair
 //	@Tags		users
 //	@Security	ApiKeyAuth
 //	@Produce	json
-//	@Param		_	body		dto.RefreshTokenReq	true	"Body"
 //	@Success	200	{object}	dto.RefreshTokenRes
-//	@Router		/auth/me [get]
+//	@Router		/auth/refresh [post]
 func (h *UserHandler) RefreshToken(c *gin.Context) {
-	userID := c.GetString("userId")
-	if userID == "" {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
 		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
 		return
 	}
 
-	accessToken, err := h.service.RefreshToken(c, userID)
+	accessToken, refreshToken, err := h.service.RefreshToken(c, principal.UserID, principal.SessionID, principal.TokenID)
+	if errors.Is(err, jtoken.ErrSessionRevoked) || errors.Is(err, jtoken.ErrTokenReused) ||
+		errors.Is(err, service.ErrUserDisabled) {
+		response.Error(c, http.StatusUnauthorized, err, "Unauthorized")
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to refresh token", err)
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
 		return
 	}
 
+	accessSet, refreshSet, err := middleware.SetTokenCookies(c, accessToken, refreshToken)
+	if err != nil {
+		logger.Error("Failed to set token cookies ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
 	res := dto.RefreshTokenRes{
-		AccessToken: accessToken,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}
+	if accessSet {
+		res.AccessToken = ""
+	}
+	if refreshSet {
+		res.RefreshToken = ""
 	}
 	response.JSON(c, http.StatusOK, res)
 }
 
+// Logout godoc
+//
+//	@Summary	revoke the current session
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Router		/auth/logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	err := h.service.Logout(c, principal.SessionOwner(), principal.SessionID)
+	if err != nil {
+		logger.Error("Failed to logout ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+	middleware.ClearTokenCookies(c)
+	response.JSON(c, http.StatusOK, nil)
+}
+
 // ChangePassword godoc
 //
 //	@Summary	changes the password
@@ -153,8 +439,13 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	userID := c.GetString("userId")
-	err := h.service.ChangePassword(c, userID, &req)
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	err := h.service.ChangePassword(c, principal.UserID, &req)
 	if err != nil {
 		logger.Error(err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -163,15 +454,77 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	response.JSON(c, http.StatusOK, nil)
 }
 
-// VerfiyCode godoc
+// ChangeEmail godoc
+//
+//	@Summary	Request a change of the email address
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body	dto.ChangeEmailReq	true	"Body"
+//	@Router		/auth/change-email [post]
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	var req dto.ChangeEmailReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	if err := h.service.ChangeEmail(c, principal.UserID, &req); err != nil {
+		emailChangeError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ConfirmEmailChange godoc
 //
-//	@Summary	Verfiy Code for phone
+//	@Summary	Confirm the new email address with the code sent to it
 //	@Tags		users
 //	@Security	ApiKeyAuth
 //	@Produce	json
+//	@Param		_	body		dto.ConfirmEmailChangeReq	true	"Body"
+//	@Success	200	{object}	dto.User
+//	@Router		/auth/change-email/confirm [post]
+func (h *UserHandler) ConfirmEmailChange(c *gin.Context) {
+	var req dto.ConfirmEmailChangeReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	user, err := h.service.ConfirmEmailChange(c, principal.UserID, &req)
+	if err != nil {
+		emailChangeError(c, err)
+		return
+	}
+
+	var res dto.User
+	utils.Copy(&res, &user)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// VerfiyCode godoc
+//
+//	@Summary	Verify email with the code sent on registration
+//	@Tags		users
+//	@Produce	json
 //	@Param		_	body	dto.VerifyRequest	true	"Body"
 //	@Success	200	{object}	dto.VerifyResponse
-//	@Router		/auth//verfiy-code [put]
+//	@Router		/auth/verify-code [put]
 func (h *UserHandler) VerfiyCode(c *gin.Context) {
 	var req dto.VerifyRequest
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
@@ -181,6 +534,10 @@ func (h *UserHandler) VerfiyCode(c *gin.Context) {
 	}
 
 	resp, err := h.service.VerifyUser(c, req)
+	if isVerifyError(err) {
+		response.Error(c, http.StatusBadRequest, err, resp.Message)
+		return
+	}
 	if err != nil {
 		logger.Error(err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -188,3 +545,644 @@ func (h *UserHandler) VerfiyCode(c *gin.Context) {
 	}
 	response.JSON(c, http.StatusOK, resp)
 }
+
+// ResendVerifyCode godoc
+//
+//	@Summary	Send a new email verification code
+//	@Tags		users
+//	@Produce	json
+//	@Param		_	body	dto.ResendVerifyCodeReq	true	"Body"
+//	@Router		/auth/resend-verify-code [post]
+func (h *UserHandler) ResendVerifyCode(c *gin.Context) {
+	var req dto.ResendVerifyCodeReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.IP = c.ClientIP()
+	err := h.service.ResendVerifyCode(c, &req)
+	var locked *throttle.LockedError
+	if errors.As(err, &locked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		response.Error(c, http.StatusTooManyRequests, err, "Too many codes requested, try again later")
+		return
+	}
+	if err != nil {
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ForgotPassword godoc
+//
+//	@Summary	Send a password reset token by email
+//	@Tags		users
+//	@Produce	json
+//	@Param		_	body	dto.ForgotPasswordReq	true	"Body"
+//	@Router		/auth/forgot-password [post]
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	err := h.service.ForgotPassword(c, &req)
+	if err != nil {
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ResetPassword godoc
+//
+//	@Summary	Reset the password with a token from forgot-password
+//	@Tags		users
+//	@Produce	json
+//	@Param		_	body	dto.ResetPasswordReq	true	"Body"
+//	@Router		/auth/reset-password [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	err := h.service.ResetPassword(c, &req)
+	if errors.Is(err, service.ErrInvalidResetToken) {
+		response.Error(c, http.StatusBadRequest, err, "Invalid or expired reset token")
+		return
+	}
+	if err != nil {
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ListUsers godoc
+//
+//	@Summary	List and search users
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Produce	json
+//	@Param		email			query		string	false	"Email contains"
+//	@Param		role			query		string	false	"Role"	Enums(admin, customer)
+//	@Param		approve			query		bool	false	"Approved"
+//	@Param		disabled		query		bool	false	"Disabled"
+//	@Param		created_from	query		string	false	"Created at or after (RFC3339)"
+//	@Param		created_to		query		string	false	"Created at or before (RFC3339)"
+//	@Param		page			query		int		false	"Page"
+//	@Param		limit			query		int		false	"Limit"
+//	@Success	200				{object}	dto.ListUsersRes
+//	@Router		/admin/users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	var req dto.ListUsersReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to parse request query: ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	users, pagination, err := h.service.ListUsers(c, &req)
+	if err != nil {
+		logger.Error("Failed to get list users: ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.ListUsersRes
+	utils.Copy(&res.Users, &users)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// UpdateRole godoc
+//
+//	@Summary	Change the role of a user
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Produce	json
+//	@Param		id	path		string				true	"User ID"
+//	@Param		_	body		dto.UpdateRoleReq	true	"Body"
+//	@Success	200	{object}	dto.UserDetail
+//	@Router		/admin/users/{id}/role [put]
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	var req dto.UpdateRoleReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	user, err := h.service.UpdateRole(c, c.Param("id"), &req)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	var res dto.UserDetail
+	utils.Copy(&res, &user)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ApproveUser godoc
+//
+//	@Summary	Approve a user without email verification
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"User ID"
+//	@Success	200	{object}	dto.UserDetail
+//	@Router		/admin/users/{id}/approve [put]
+func (h *UserHandler) ApproveUser(c *gin.Context) {
+	user, err := h.service.ApproveUser(c, c.Param("id"))
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	var res dto.UserDetail
+	utils.Copy(&res, &user)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// SetDisabled godoc
+//
+//	@Summary	Disable or re-enable a user
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Produce	json
+//	@Param		id	path		string				true	"User ID"
+//	@Param		_	body		dto.SetDisabledReq	true	"Body"
+//	@Success	200	{object}	dto.UserDetail
+//	@Router		/admin/users/{id}/disabled [put]
+func (h *UserHandler) SetDisabled(c *gin.Context) {
+	var req dto.SetDisabledReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	user, err := h.service.SetDisabled(c, c.Param("id"), req.Disabled)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	var res dto.UserDetail
+	utils.Copy(&res, &user)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// UnlockUser godoc
+//
+//	@Summary	Clear the login lockout of a user, and of a client IP if given
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Produce	json
+//	@Param		id	path	string	true	"User ID"
+//	@Param		ip	query	string	false	"Client IP"
+//	@Router		/admin/users/{id}/unlock [put]
+func (h *UserHandler) UnlockUser(c *gin.Context) {
+	var req dto.UnlockUserReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to parse request query: ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	if err := h.service.UnlockUser(c, c.Param("id"), &req); err != nil {
+		adminError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ListAuditEvents godoc
+//
+//	@Summary	List authentication audit events
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Security	MachineKeyAuth
+//	@Produce	json
+//	@Param		user_id		query		string	false	"User ID"
+//	@Param		email		query		string	false	"Email contains"
+//	@Param		type		query		string	false	"Event type"	Enums(login, login_otp, login_mfa, mfa_challenge, register, verify_email, password_change, password_reset, token_refresh, logout)
+//	@Param		outcome		query		string	false	"Outcome"		Enums(success, failure)
+//	@Param		transport	query		string	false	"Transport"		Enums(http, grpc)
+//	@Param		ip			query		string	false	"Client IP"
+//	@Param		from		query		string	false	"Created at or after (RFC3339)"
+//	@Param		to			query		string	false	"Created at or before (RFC3339)"
+//	@Param		page		query		int		false	"Page"
+//	@Param		limit		query		int		false	"Limit"
+//	@Success	200			{object}	dto.ListAuditEventsRes
+//	@Router		/admin/audit-events [get]
+func (h *UserHandler) ListAuditEvents(c *gin.Context) {
+	var req dto.ListAuditEventsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to parse request query: ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	events, pagination, err := h.service.ListAuditEvents(c, &req)
+	if err != nil {
+		logger.Error("Failed to list audit events: ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.ListAuditEventsRes
+	utils.Copy(&res.Events, &events)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// Impersonate godoc
+//
+//	@Summary	Get a short-lived token acting as a user
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string				true	"User ID"
+//	@Param		_	body		dto.ImpersonateReq	true	"Body"
+//	@Success	200	{object}	dto.ImpersonateRes
+//	@Router		/admin/users/{id}/impersonate [post]
+func (h *UserHandler) Impersonate(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	var req dto.ImpersonateReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	res, err := h.service.Impersonate(c, principal.UserID, principal.SessionID, c.Param("id"), &req)
+	if err != nil {
+		impersonationError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, res)
+}
+
+// CreateAPIKey godoc
+//
+//	@Summary	Create an API key for a machine client
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.CreateAPIKeyReq	true	"Body"
+//	@Success	200	{object}	dto.CreateAPIKeyRes
+//	@Router		/admin/api-keys [post]
+func (h *UserHandler) CreateAPIKey(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	var req dto.CreateAPIKeyReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	res, err := h.service.CreateAPIKey(c, principal.UserID, &req)
+	if err != nil {
+		apiKeyError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListAPIKeys godoc
+//
+//	@Summary	List API keys
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListAPIKeysRes
+//	@Router		/admin/api-keys [get]
+func (h *UserHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.service.ListAPIKeys(c)
+	if err != nil {
+		apiKeyError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, dto.ListAPIKeysRes{APIKeys: keys})
+}
+
+// RevokeAPIKey godoc
+//
+//	@Summary	Revoke an API key
+//	@Tags		admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string	true	"API key ID"
+//	@Router		/admin/api-keys/{id} [delete]
+func (h *UserHandler) RevokeAPIKey(c *gin.Context) {
+	if err := h.service.RevokeAPIKey(c, c.Param("id")); err != nil {
+		apiKeyError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// EnrollTOTP godoc
+//
+//	@Summary	Start two-factor authentication enrollment
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.EnrollTOTPRes
+//	@Router		/auth/2fa/enroll [post]
+func (h *UserHandler) EnrollTOTP(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	res, err := h.service.EnrollTOTP(c, principal.UserID)
+	if err != nil {
+		mfaError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ConfirmTOTP godoc
+//
+//	@Summary	Enable two-factor authentication with a code from the app
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.TOTPCodeReq	true	"Body"
+//	@Success	200	{object}	dto.ConfirmTOTPRes
+//	@Router		/auth/2fa/confirm [post]
+func (h *UserHandler) ConfirmTOTP(c *gin.Context) {
+	var req dto.TOTPCodeReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	res, err := h.service.ConfirmTOTP(c, principal.UserID, &req)
+	if err != nil {
+		mfaError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, res)
+}
+
+// DisableTOTP godoc
+//
+//	@Summary	Disable two-factor authentication
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body	dto.TOTPCodeReq	true	"Body"
+//	@Router		/auth/2fa/disable [post]
+func (h *UserHandler) DisableTOTP(c *gin.Context) {
+	var req dto.TOTPCodeReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	if err := h.service.DisableTOTP(c, principal.UserID, &req); err != nil {
+		mfaError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ListSessions godoc
+//
+//	@Summary	List the devices the user is logged in on
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListSessionsRes
+//	@Router		/auth/sessions [get]
+func (h *UserHandler) ListSessions(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	sessions, err := h.service.ListSessions(c, principal.UserID, principal.SessionID)
+	if err != nil {
+		logger.Error("Failed to list sessions ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+	response.JSON(c, http.StatusOK, dto.ListSessionsRes{Sessions: sessions})
+}
+
+// RevokeSession godoc
+//
+//	@Summary	Log out of a device
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string	true	"Session ID"
+//	@Router		/auth/sessions/{id} [delete]
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+
+	err := h.service.RevokeSession(c, principal.UserID, c.Param("id"))
+	if errors.Is(err, service.ErrSessionNotFound) {
+		response.Error(c, http.StatusNotFound, err, "Session not found")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to revoke session ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// writeLogin responds with a successful login. In cookie auth mode the tokens
+// are set as cookies and left out of the body.
+func writeLogin(c *gin.Context, res *dto.LoginRes) {
+	if !res.MFARequired {
+		accessSet, refreshSet, err := middleware.SetTokenCookies(c, res.AccessToken, res.RefreshToken)
+		if err != nil {
+			logger.Error("Failed to set token cookies ", err)
+			response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+			return
+		}
+		if accessSet {
+			res.AccessToken = ""
+		}
+		if refreshSet {
+			res.RefreshToken = ""
+		}
+	}
+	response.JSON(c, http.StatusOK, res)
+}
+
+// loginError writes the response for a failed Login or LoginMFA.
+func loginError(c *gin.Context, err error) {
+	var locked *throttle.LockedError
+	switch {
+	case errors.As(err, &locked):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		response.Error(c, http.StatusTooManyRequests, err, "Too many login attempts, try again later")
+	case errors.Is(err, service.ErrEmailNotVerified):
+		response.Error(c, http.StatusForbidden, err, "Email is not verified")
+	case errors.Is(err, service.ErrUserDisabled):
+		response.Error(c, http.StatusForbidden, err, "Account is disabled")
+	case errors.Is(err, otp.ErrInvalidCode):
+		response.Error(c, http.StatusUnauthorized, err, "Invalid or expired code")
+	case errors.Is(err, service.ErrInvalidMFAToken):
+		response.Error(c, http.StatusUnauthorized, err, "Invalid or expired MFA token")
+	case errors.Is(err, service.ErrWrongMFACode):
+		response.Error(c, http.StatusUnauthorized, err, "Invalid code")
+	default:
+		logger.Error("Failed to login ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
+
+// oidcError writes the response for a failed OpenID Connect login.
+func oidcError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOIDCDisabled):
+		response.Error(c, http.StatusNotFound, err, "Not found")
+	case errors.Is(err, oidc.ErrInvalidState):
+		response.Error(c, http.StatusBadRequest, err, "Invalid or expired login, try again")
+	case errors.Is(err, oidc.ErrExchangeFailed), errors.Is(err, oidc.ErrInvalidIDToken):
+		response.Error(c, http.StatusUnauthorized, err, "Login was refused by the provider")
+	case errors.Is(err, oidc.ErrUnverifiedEmail):
+		response.Error(c, http.StatusForbidden, err, "Email is not verified by the provider")
+	default:
+		loginError(c, err)
+	}
+}
+
+func mfaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrWrongMFACode):
+		response.Error(c, http.StatusBadRequest, err, "Invalid code")
+	case errors.Is(err, service.ErrMFAAlreadyEnabled):
+		response.Error(c, http.StatusBadRequest, err, "Two-factor authentication already enabled")
+	case errors.Is(err, service.ErrMFANotEnrolled):
+		response.Error(c, http.StatusBadRequest, err, "Two-factor authentication not enrolled")
+	default:
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
+
+func adminError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response.Error(c, http.StatusNotFound, err, "Not found")
+		return
+	}
+
+	logger.Error(err.Error())
+	response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+}
+
+func impersonationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.Error(c, http.StatusNotFound, err, "User not found")
+	case errors.Is(err, service.ErrCannotImpersonate):
+		response.Error(c, http.StatusForbidden, err, "User cannot be impersonated")
+	case errors.Is(err, service.ErrUserDisabled):
+		response.Error(c, http.StatusBadRequest, err, "Account is disabled")
+	default:
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
+
+func apiKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.Error(c, http.StatusNotFound, err, "User not found")
+	case errors.Is(err, apikey.ErrKeyNotFound):
+		response.Error(c, http.StatusNotFound, err, "Not found")
+	case errors.Is(err, service.ErrUserDisabled):
+		response.Error(c, http.StatusBadRequest, err, "Account is disabled")
+	case errors.Is(err, service.ErrInvalidExpiry):
+		response.Error(c, http.StatusBadRequest, err, "Expiry must be in the future")
+	default:
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
+
+func emailChangeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrEmailTaken):
+		response.Error(c, http.StatusConflict, err, "Email already in use")
+	case errors.Is(err, service.ErrWrongPassword):
+		response.Error(c, http.StatusBadRequest, err, "Wrong password")
+	case errors.Is(err, service.ErrSameEmail):
+		response.Error(c, http.StatusBadRequest, err, "Email is the current address")
+	case errors.Is(err, service.ErrNoPendingEmail):
+		response.Error(c, http.StatusBadRequest, err, "No email change pending")
+	case errors.Is(err, service.ErrWrongVerifyCode):
+		response.Error(c, http.StatusBadRequest, err, "Verify code not correct")
+	case errors.Is(err, service.ErrVerifyCodeExpired):
+		response.Error(c, http.StatusBadRequest, err, "Verify code expired, request a new code")
+	case errors.Is(err, service.ErrTooManyAttempts):
+		response.Error(c, http.StatusBadRequest, err, "Too many attempts, request a new code")
+	default:
+		logger.Error("Failed to change email ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
+
+func isVerifyError(err error) bool {
+	return errors.Is(err, service.ErrAlreadyVerified) ||
+		errors.Is(err, service.ErrWrongVerifyCode) ||
+		errors.Is(err, service.ErrVerifyCodeExpired) ||
+		errors.Is(err, service.ErrTooManyAttempts)
+}
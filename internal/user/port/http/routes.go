@@ -4,25 +4,76 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/quangdangfit/gocommon/validation"
 
-	"main/internal/user/repository"
+	"main/internal/user/model"
 	"main/internal/user/service"
+	"main/pkg/apikey"
+	"main/pkg/config"
 	"main/pkg/dbs"
 	"main/pkg/middleware"
+	"main/pkg/redis"
 )
 
-func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation) {
-	userRepo := repository.NewUserRepository(sqlDB)
-	userSvc := service.NewUserService(validator, userRepo)
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, cache redis.IRedis) {
+	apiKeys := apikey.NewStore(sqlDB)
+	userSvc := service.NewFromConfig(config.GetConfig(), sqlDB, cache, validator)
 	userHandler := NewUserHandler(userSvc)
 
-	authMiddleware := middleware.JWTAuth()
-	refreshAuthMiddleware := middleware.JWTRefresh()
+	authMiddleware := middleware.JWTAuth(cache)
+	keyAuthMiddleware := middleware.JWTAuthOrAPIKey(cache, apiKeys)
+	refreshAuthMiddleware := middleware.JWTRefresh(cache)
 	authRoute := r.Group("/auth")
 	{
 		authRoute.POST("/register", userHandler.Register)
 		authRoute.POST("/login", userHandler.Login)
+		authRoute.POST("/login/mfa", userHandler.LoginMFA)
+		authRoute.POST("/otp/request", userHandler.RequestOTP)
+		authRoute.POST("/otp/verify", userHandler.VerifyOTP)
+		authRoute.GET("/oidc/login", userHandler.OIDCLogin)
+		authRoute.GET("/oidc/callback", userHandler.OIDCCallback)
+		authRoute.PUT("/verify-code", userHandler.VerfiyCode)
+		authRoute.POST("/resend-verify-code", userHandler.ResendVerifyCode)
 		authRoute.POST("/refresh", refreshAuthMiddleware, userHandler.RefreshToken)
+		authRoute.POST("/logout", authMiddleware, userHandler.Logout)
 		authRoute.GET("/me", authMiddleware, userHandler.GetMe)
+		authRoute.PATCH("/me", authMiddleware, userHandler.UpdateProfile)
+		authRoute.DELETE("/me", authMiddleware, userHandler.DeleteAccount)
+		authRoute.GET("/me/export", authMiddleware, userHandler.ExportAccount)
 		authRoute.PUT("/change-password", authMiddleware, userHandler.ChangePassword)
+		authRoute.POST("/change-email", authMiddleware, userHandler.ChangeEmail)
+		authRoute.POST("/change-email/confirm", authMiddleware, userHandler.ConfirmEmailChange)
+		authRoute.POST("/forgot-password", userHandler.ForgotPassword)
+		authRoute.POST("/reset-password", userHandler.ResetPassword)
+		authRoute.POST("/2fa/enroll", authMiddleware, userHandler.EnrollTOTP)
+		authRoute.POST("/2fa/confirm", authMiddleware, userHandler.ConfirmTOTP)
+		authRoute.POST("/2fa/disable", authMiddleware, userHandler.DisableTOTP)
+		authRoute.GET("/sessions", authMiddleware, userHandler.ListSessions)
+		authRoute.DELETE("/sessions/:id", authMiddleware, userHandler.RevokeSession)
+	}
+
+	adminRoute := r.Group("/admin/users", keyAuthMiddleware, middleware.RequireRole(string(model.UserRoleAdmin)))
+	{
+		readScope := middleware.RequireScope(apikey.ScopeUsersRead)
+		writeScope := middleware.RequireScope(apikey.ScopeUsersWrite)
+		adminRoute.GET("", readScope, userHandler.ListUsers)
+		adminRoute.PUT("/:id/role", writeScope, userHandler.UpdateRole)
+		adminRoute.PUT("/:id/approve", writeScope, userHandler.ApproveUser)
+		adminRoute.PUT("/:id/disabled", writeScope, userHandler.SetDisabled)
+		adminRoute.PUT("/:id/unlock", writeScope, userHandler.UnlockUser)
+	}
+
+	auditRoute := r.Group("/admin/audit-events", keyAuthMiddleware, middleware.RequireRole(string(model.UserRoleAdmin)))
+	{
+		auditRoute.GET("", middleware.RequireScope(apikey.ScopeAuditRead), userHandler.ListAuditEvents)
+	}
+
+	// Impersonation needs the admin's session, which API keys do not have.
+	r.POST("/admin/users/:id/impersonate", authMiddleware, middleware.RequireRole(string(model.UserRoleAdmin)), userHandler.Impersonate)
+
+	// API keys are managed by admins that logged in, never with another key.
+	apiKeyRoute := r.Group("/admin/api-keys", authMiddleware, middleware.RequireRole(string(model.UserRoleAdmin)))
+	{
+		apiKeyRoute.POST("", userHandler.CreateAPIKey)
+		apiKeyRoute.GET("", userHandler.ListAPIKeys)
+		apiKeyRoute.DELETE("/:id", userHandler.RevokeAPIKey)
 	}
 }
@@ -2,12 +2,17 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
+	addressModel "main/internal/address/model"
+	"main/internal/user/dto"
 	"main/internal/user/model"
+	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/paging"
 )
 
 //go:generate mockery --name=IUserRepository
@@ -16,8 +21,19 @@ type IUserRepository interface {
 	Update(ctx context.Context, user *model.User) error
 	GetUserByID(ctx context.Context, id string) (*model.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
-	FindByEmailAndVerifyCode(ctx context.Context, email, verifyCode string) (*model.User, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	GetUserByPhone(ctx context.Context, phone string) (*model.User, error)
+	PhoneExists(ctx context.Context, phone string) (bool, error)
+	GetUserByResetTokenHash(ctx context.Context, hash string) (*model.User, error)
 	UpdateUser(ctx context.Context, user *model.User) error
+	CountVerifyAttempt(ctx context.Context, id string, max int) (bool, error)
+	CountEmailChangeAttempt(ctx context.Context, id string, max int) (bool, error)
+	UseTOTPStep(ctx context.Context, id string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, id, hashes, remaining string) (bool, error)
+	ListUsers(ctx context.Context, req *dto.ListUsersReq) ([]*model.User, *paging.Pagination, error)
+	ListUserAddresses(ctx context.Context, userID string) ([]*addressModel.Address, error)
+	DeleteAccount(ctx context.Context, user *model.User) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 type UserRepo struct {
@@ -55,17 +71,52 @@ func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*model.Use
 
 	return &user, nil
 }
-func (r *UserRepo) FindByEmailAndVerifyCode(ctx context.Context, email, verifyCode string) (*model.User, error) {
+
+// EmailExists also counts deleted users, whose emails stay in the unique index.
+func (r *UserRepo) EmailExists(ctx context.Context, email string) (bool, error) {
+	var total int64
+	err := r.db.GetDB().WithContext(ctx).Unscoped().
+		Model(&model.User{}).
+		Where("email = ?", email).
+		Count(&total).Error
+	if err != nil {
+		return false, err
+	}
+
+	return total > 0, nil
+}
+
+func (r *UserRepo) GetUserByPhone(ctx context.Context, phone string) (*model.User, error) {
 	var user model.User
-	query := "SELECT id, email, verify_code, approve FROM users WHERE email = ? AND verify_code = ?"
+	query := dbs.NewQuery("phone = ?", phone)
+	if err := r.db.FindOne(ctx, &user, dbs.WithQuery(query)); err != nil {
+		return nil, err
+	}
 
-	result := r.db.GetDB().WithContext(ctx).Raw(query, email, verifyCode).Scan(&user)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
+	return &user, nil
+}
+
+// PhoneExists also counts deleted users, whose phones stay in the unique index.
+func (r *UserRepo) PhoneExists(ctx context.Context, phone string) (bool, error) {
+	var total int64
+	err := r.db.GetDB().WithContext(ctx).Unscoped().
+		Model(&model.User{}).
+		Where("phone = ?", phone).
+		Count(&total).Error
+	if err != nil {
+		return false, err
 	}
+
+	return total > 0, nil
+}
+
+func (r *UserRepo) GetUserByResetTokenHash(ctx context.Context, hash string) (*model.User, error) {
+	var user model.User
+	query := dbs.NewQuery("reset_token_hash = ?", hash)
+	if err := r.db.FindOne(ctx, &user, dbs.WithQuery(query)); err != nil {
+		return nil, err
+	}
+
 	return &user, nil
 }
 
@@ -74,3 +125,159 @@ func (r *UserRepo) UpdateUser(ctx context.Context, user *model.User) error {
 	err := r.db.Exec(ctx, query, user.Approve, user.Email)
 	return err
 }
+
+// CountVerifyAttempt counts an attempt at the email verification code of the
+// user, and reports false without counting it once max attempts were made.
+func (r *UserRepo) CountVerifyAttempt(ctx context.Context, id string, max int) (bool, error) {
+	return r.countAttempt(ctx, id, "verify_attempts", max)
+}
+
+// CountEmailChangeAttempt counts an attempt at the code confirming a pending
+// email change, like CountVerifyAttempt.
+func (r *UserRepo) CountEmailChangeAttempt(ctx context.Context, id string, max int) (bool, error) {
+	return r.countAttempt(ctx, id, "email_change_attempts", max)
+}
+
+// countAttempt increments column in one statement that also checks the limit,
+// so that parallel attempts cannot all read the same count.
+func (r *UserRepo) countAttempt(ctx context.Context, id, column string, max int) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND "+column+" < ?", id, max).
+		UpdateColumn(column, gorm.Expr(column+" + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UseTOTPStep records that the TOTP code of step was used, and reports false if
+// a code of that step or a later one was used already.
+func (r *UserRepo) UseTOTPStep(ctx context.Context, id string, step int64) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		UpdateColumn("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UseRecoveryCode replaces the recovery code hashes of the user with remaining,
+// and reports false if they are no longer hashes because another code was used
+// in the meantime.
+func (r *UserRepo) UseRecoveryCode(ctx context.Context, id, hashes, remaining string) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND recovery_code_hashes = ?", id, hashes).
+		UpdateColumn("recovery_code_hashes", remaining)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *UserRepo) ListUsers(ctx context.Context, req *dto.ListUsersReq) ([]*model.User, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+	defer cancel()
+
+	query := make([]dbs.Query, 0)
+	if req.Email != "" {
+		query = append(query, dbs.NewQuery("email LIKE ?", "%"+req.Email+"%"))
+	}
+	if req.Role != "" {
+		query = append(query, dbs.NewQuery("role = ?", req.Role))
+	}
+	if req.Approve != nil {
+		query = append(query, dbs.NewQuery("approve = ?", *req.Approve))
+	}
+	if req.Disabled != nil {
+		query = append(query, dbs.NewQuery("disabled = ?", *req.Disabled))
+	}
+	if req.CreatedFrom != nil {
+		query = append(query, dbs.NewQuery("created_at >= ?", *req.CreatedFrom))
+	}
+	if req.CreatedTo != nil {
+		query = append(query, dbs.NewQuery("created_at <= ?", *req.CreatedTo))
+	}
+
+	var total int64
+	if err := r.db.Count(ctx, &model.User{}, &total, dbs.WithQuery(query...)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var users []*model.User
+	if err := r.db.Find(
+		ctx,
+		&users,
+		dbs.WithQuery(query...),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("created_at DESC"),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return users, pagination, nil
+}
+
+func (r *UserRepo) ListUserAddresses(ctx context.Context, userID string) ([]*addressModel.Address, error) {
+	var addresses []*addressModel.Address
+	query := dbs.NewQuery("id_user = ?", userID)
+	if err := r.db.Find(ctx, &addresses, dbs.WithQuery(query), dbs.WithOrder("created_at")); err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+// DeleteAccount soft deletes the user together with their addresses. The email
+// and phone are unique among deleted users too, so they are replaced first to
+// let them be used for a new account.
+func (r *UserRepo) DeleteAccount(ctx context.Context, user *model.User) error {
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id_user = ?", user.ID).Delete(&addressModel.Address{}).Error; err != nil {
+			return err
+		}
+
+		released := map[string]interface{}{
+			"email": fmt.Sprintf("deleted+%s@deleted.invalid", user.ID),
+			"phone": "",
+		}
+		if err := tx.Model(user).Updates(released).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(user).Error
+	})
+}
+
+// PurgeDeleted permanently removes the users deleted before the given time with
+// all their addresses, as well as any address deleted before that time, and
+// returns the number of users removed.
+func (r *UserRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deleted := tx.Unscoped().Model(&model.User{}).
+			Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+
+		if err := tx.Unscoped().
+			Where("id_user IN (?) OR (deleted_at IS NOT NULL AND deleted_at < ?)", deleted, before).
+			Delete(&addressModel.Address{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Delete(&model.User{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+
+	return purged, err
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/pkg/password"
+	"main/pkg/utils"
+)
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatZIP  = "zip"
+)
+
+// addressCachePattern matches the address responses cached by the address
+// module.
+const addressCachePattern = "address:*"
+
+// ExportAccount collects the personal data held about a user, as a single
+// JSON document or as a ZIP archive with one JSON file per kind of data.
+func (s *UserService) ExportAccount(ctx context.Context, id string, req *dto.ExportAccountReq) (*dto.ExportFile, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("ExportAccount.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	addresses, err := s.repo.ListUserAddresses(ctx, id)
+	if err != nil {
+		logger.Errorf("ExportAccount.ListUserAddresses fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	sessions, err := s.ListSessions(ctx, id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	export := dto.AccountExport{
+		ExportedAt:  time.Now(),
+		TOTPEnabled: user.TOTPEnabled,
+		Addresses:   addresses,
+		Sessions:    sessions,
+	}
+	utils.Copy(&export.User, user)
+
+	if req.Format == ExportFormatZIP {
+		return zipExport(&export)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ExportFile{
+		Name:        "account.json",
+		ContentType: "application/json",
+		Data:        data,
+	}, nil
+}
+
+func zipExport(export *dto.AccountExport) (*dto.ExportFile, error) {
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "user.json", value: export.User},
+		{name: "addresses.json", value: export.Addresses},
+		{name: "sessions.json", value: export.Sessions},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(file.value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return &dto.ExportFile{
+		Name:        "account.zip",
+		ContentType: "application/zip",
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// DeleteAccount soft deletes the user and their addresses and ends every
+// session and API key. The data is purged once the deletion grace period is
+// over.
+func (s *UserService) DeleteAccount(ctx context.Context, id string, req *dto.DeleteAccountReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("DeleteAccount.GetUserByID fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	if err = password.Compare(user.Password, req.Password); err != nil {
+		return ErrWrongPassword
+	}
+
+	if err = s.repo.DeleteAccount(ctx, user); err != nil {
+		logger.Errorf("DeleteAccount fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	// The addresses are gone, so are the cached responses listing them.
+	if err = s.cache.RemovePattern(addressCachePattern); err != nil {
+		logger.Errorf("DeleteAccount.RemovePattern fail, id: %s, error: %s", id, err)
+	}
+
+	if err = s.tokenStore.RevokeAll(id); err != nil {
+		logger.Errorf("DeleteAccount.RevokeAll fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	if err = s.apiKeys.RevokeAll(ctx, id); err != nil {
+		logger.Errorf("DeleteAccount.RevokeAll keys fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	return nil
+}
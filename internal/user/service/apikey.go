@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/pkg/apikey"
+	"main/pkg/utils"
+)
+
+var ErrInvalidExpiry = errors.New("expiry must be in the future")
+
+// CreateAPIKey creates a key for a machine client. The key acts as the user
+// it is created for, with that user's role, but only within its scopes.
+func (s *UserService) CreateAPIKey(ctx context.Context, adminID string, req *dto.CreateAPIKeyReq) (*dto.CreateAPIKeyRes, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	userID := req.UserID
+	if userID == "" {
+		userID = adminID
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("CreateAPIKey.GetUserByID fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	key := &apikey.Key{
+		Name:      req.Name,
+		UserID:    user.ID,
+		Role:      string(user.Role),
+		Scopes:    req.Scopes,
+		CreatedBy: adminID,
+		ExpiresAt: req.ExpiresAt,
+	}
+	raw, err := s.apiKeys.Create(ctx, key)
+	if err != nil {
+		logger.Errorf("CreateAPIKey.Create fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	res := &dto.CreateAPIKeyRes{Key: raw}
+	utils.Copy(&res.APIKey, key)
+	return res, nil
+}
+
+// ListAPIKeys returns every key, revoked and expired ones included.
+func (s *UserService) ListAPIKeys(ctx context.Context) ([]*dto.APIKey, error) {
+	keys, err := s.apiKeys.List(ctx)
+	if err != nil {
+		logger.Errorf("ListAPIKeys.List fail, error: %s", err)
+		return nil, err
+	}
+
+	var res []*dto.APIKey
+	utils.Copy(&res, &keys)
+	return res, nil
+}
+
+// RevokeAPIKey stops a key from being accepted. The key stays listed.
+func (s *UserService) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := s.apiKeys.Revoke(ctx, id); err != nil {
+		if !errors.Is(err, apikey.ErrKeyNotFound) {
+			logger.Errorf("RevokeAPIKey.Revoke fail, id: %s, error: %s", id, err)
+		}
+		return err
+	}
+
+	return nil
+}
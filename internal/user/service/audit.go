@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/paging"
+)
+
+// record saves an audit event of eventType. userID is empty when the user is
+// not known, like on a login with an unknown email. The event is a failure
+// when err is set.
+func (s *UserService) record(ctx context.Context, eventType, userID, email string, err error) {
+	event := &audit.Event{
+		Type:    eventType,
+		Outcome: audit.OutcomeSuccess,
+		UserID:  userID,
+		Email:   email,
+	}
+	if err != nil {
+		event.Outcome = audit.OutcomeFailure
+		event.Reason = err.Error()
+	}
+
+	s.audit.Record(ctx, event)
+}
+
+// idOf returns the id of user, which may not have been found.
+func idOf(user *model.User) string {
+	if user == nil {
+		return ""
+	}
+	return user.ID
+}
+
+// loginEvent is eventType, or EventMFAChallenge when the login still waits
+// for a second factor.
+func loginEvent(eventType string, res *dto.LoginRes) string {
+	if res != nil && res.MFARequired {
+		return audit.EventMFAChallenge
+	}
+	return eventType
+}
+
+func (s *UserService) ListAuditEvents(ctx context.Context, req *dto.ListAuditEventsReq) ([]*audit.Event, *paging.Pagination, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, nil, err
+	}
+
+	events, pagination, err := s.audit.List(ctx, &audit.Filter{
+		UserID:    req.UserID,
+		ActorID:   req.ActorID,
+		Email:     req.Email,
+		Type:      req.Type,
+		Outcome:   req.Outcome,
+		Transport: req.Transport,
+		IP:        req.IP,
+		From:      req.From,
+		To:        req.To,
+		Page:      req.Page,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		logger.Errorf("ListAuditEvents.List fail, error: %s", err)
+		return nil, nil, err
+	}
+
+	return events, pagination, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/pkg/config"
+	"main/pkg/password"
+	"main/pkg/utils"
+)
+
+var (
+	ErrEmailTaken     = errors.New("email already in use")
+	ErrSameEmail      = errors.New("email is the current address")
+	ErrNoPendingEmail = errors.New("no email change pending")
+)
+
+// ChangeEmail stores the new address as pending and mails a confirmation code
+// to it. The current address is told about the request, so that the owner
+// notices if the account was taken over.
+func (s *UserService) ChangeEmail(ctx context.Context, id string, req *dto.ChangeEmailReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("ChangeEmail.GetUserByID fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	if err = password.Compare(user.Password, req.Password); err != nil {
+		return ErrWrongPassword
+	}
+
+	if req.Email == user.Email {
+		return ErrSameEmail
+	}
+
+	if err = s.checkEmailAvailable(ctx, req.Email); err != nil {
+		return err
+	}
+
+	code, err := utils.GenerateNumericCode(config.VerifyCodeLength)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(config.VerifyCodeExpiredTime)
+	user.PendingEmail = req.Email
+	user.EmailChangeCodeHash = utils.HashToken(code)
+	user.EmailChangeExpiresAt = &expiresAt
+	user.EmailChangeAttempts = 0
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("ChangeEmail.Update fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Your code to confirm this email address is %s. It expires in %d minutes.",
+		code,
+		int(config.VerifyCodeExpiredTime.Minutes()),
+	)
+	if err = s.mailer.Send(user.PendingEmail, "Confirm your new email", body); err != nil {
+		logger.Errorf("ChangeEmail.Send fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	body = fmt.Sprintf(
+		"A change of the email address of your account to %s was requested. If this was not you, change your password now.",
+		user.PendingEmail,
+	)
+	if err = s.mailer.Send(user.Email, "Email change requested", body); err != nil {
+		logger.Errorf("ChangeEmail.Send fail, id: %s, error: %s", id, err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange swaps in the pending address once its code is confirmed.
+// Tokens carry the email, so every session of the user is revoked.
+func (s *UserService) ConfirmEmailChange(ctx context.Context, id string, req *dto.ConfirmEmailChangeReq) (*model.User, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("ConfirmEmailChange.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	if user.PendingEmail == "" {
+		return nil, ErrNoPendingEmail
+	}
+
+	if user.EmailChangeExpiresAt == nil || time.Now().After(*user.EmailChangeExpiresAt) {
+		return nil, ErrVerifyCodeExpired
+	}
+
+	counted, err := s.repo.CountEmailChangeAttempt(ctx, id, config.VerifyCodeMaxAttempts)
+	if err != nil {
+		logger.Errorf("ConfirmEmailChange.CountEmailChangeAttempt fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	if !counted {
+		return nil, ErrTooManyAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(user.EmailChangeCodeHash), []byte(utils.HashToken(req.Code))) != 1 {
+		return nil, ErrWrongVerifyCode
+	}
+
+	// Someone may have registered the address since the change was requested.
+	if err = s.checkEmailAvailable(ctx, user.PendingEmail); err != nil {
+		clearEmailChange(user)
+		if updateErr := s.repo.Update(ctx, user); updateErr != nil {
+			logger.Errorf("ConfirmEmailChange.Update fail, id: %s, error: %s", id, updateErr)
+		}
+		return nil, err
+	}
+
+	user.Email = user.PendingEmail
+	user.Approve = true
+	clearEmailChange(user)
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("ConfirmEmailChange.Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	if err = s.tokenStore.RevokeAll(user.ID); err != nil {
+		logger.Errorf("ConfirmEmailChange.RevokeAll fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) checkEmailAvailable(ctx context.Context, email string) error {
+	exists, err := s.repo.EmailExists(ctx, email)
+	if err != nil {
+		logger.Errorf("EmailExists fail, email: %s, error: %s", email, err)
+		return err
+	}
+
+	if exists {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+func clearEmailChange(user *model.User) {
+	user.PendingEmail = ""
+	user.EmailChangeCodeHash = ""
+	user.EmailChangeExpiresAt = nil
+	user.EmailChangeAttempts = 0
+}
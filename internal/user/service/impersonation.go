@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/jtoken"
+	"main/pkg/utils"
+)
+
+var ErrCannotImpersonate = errors.New("user cannot be impersonated")
+
+// Impersonate returns a short-lived access token that lets the admin adminID
+// act as the user id. The token lives in the admin's session sessionID and
+// ends with it. Admins cannot be impersonated, so an impersonation never
+// gains a role.
+func (s *UserService) Impersonate(ctx context.Context, adminID, sessionID, id string, req *dto.ImpersonateReq) (res *dto.ImpersonateRes, err error) {
+	defer func() {
+		event := &audit.Event{
+			Type:    audit.EventImpersonationStart,
+			Outcome: audit.OutcomeSuccess,
+			UserID:  id,
+			ActorID: adminID,
+			Reason:  req.Reason,
+		}
+		if err != nil {
+			event.Outcome = audit.OutcomeFailure
+			event.Reason = err.Error()
+		}
+		s.audit.Record(ctx, event)
+	}()
+
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	if sessionID == "" {
+		return nil, ErrCannotImpersonate
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("Impersonate.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	if user.ID == adminID || user.Role == model.UserRoleAdmin {
+		return nil, ErrCannotImpersonate
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	accessToken := jtoken.GenerateImpersonationToken(jtoken.Claims{
+		Subject:   user.ID,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		SessionID: sessionID,
+		Actor:     &jtoken.Actor{Subject: adminID},
+		ReadOnly:  req.ReadOnly,
+	})
+	if accessToken == "" {
+		return nil, errors.New("failed to sign impersonation token")
+	}
+
+	res = &dto.ImpersonateRes{
+		AccessToken: accessToken,
+		ExpiresAt:   time.Now().Add(jtoken.ImpersonationTokenExpiredTime * time.Second),
+		ReadOnly:    req.ReadOnly,
+	}
+	utils.Copy(&res.User, user)
+	return res, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"gorm.io/gorm"
+
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/otp"
+)
+
+// RequestOTP sends a login code to the phone. Unknown numbers are not
+// reported and count against the same limits, so that the endpoint cannot be
+// used to discover accounts.
+func (s *UserService) RequestOTP(ctx context.Context, req *dto.RequestOTPReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	if err := s.otp.Reserve(req.Phone, req.IP); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetUserByPhone(ctx, req.Phone); err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Errorf("RequestOTP.GetUserByPhone fail, phone: %s, error: %s", req.Phone, err)
+			return err
+		}
+		logger.Infof("RequestOTP unknown phone: %s", req.Phone)
+		return nil
+	}
+
+	return s.otp.Send(req.Phone)
+}
+
+// VerifyOTP logs the owner of the phone in with a code from RequestOTP.
+func (s *UserService) VerifyOTP(ctx context.Context, req *dto.VerifyOTPReq) (res *dto.LoginRes, err error) {
+	var user *model.User
+	defer func() { s.record(ctx, loginEvent(audit.EventLoginOTP, res), idOf(user), "", err) }()
+
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.otp.Verify(req.Phone, req.IP, req.Code); err != nil {
+		return nil, err
+	}
+
+	user, err = s.repo.GetUserByPhone(ctx, req.Phone)
+	if err != nil {
+		logger.Errorf("VerifyOTP.GetUserByPhone fail, phone: %s, error: %s", req.Phone, err)
+		return nil, otp.ErrInvalidCode
+	}
+
+	if !user.PhoneVerified {
+		user.PhoneVerified = true
+		if err = s.repo.Update(ctx, user); err != nil {
+			logger.Errorf("VerifyOTP.Update fail, id: %s, error: %s", user.ID, err)
+			return nil, err
+		}
+	}
+
+	return s.completeLogin(user, strings.ToLower(user.Email), req.IP, req.UserAgent)
+}
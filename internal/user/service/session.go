@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/pkg/jtoken"
+	"main/pkg/utils"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// ListSessions returns the devices the user is logged in on, marking the one
+// of currentSessionID.
+func (s *UserService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*dto.Session, error) {
+	sessions, err := s.tokenStore.List(userID)
+	if err != nil {
+		logger.Errorf("ListSessions.List fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	var res []*dto.Session
+	utils.Copy(&res, &sessions)
+	for _, session := range res {
+		session.Current = session.ID == currentSessionID
+	}
+
+	return res, nil
+}
+
+// RevokeSession logs the user out of one device. Its access token is rejected
+// right away and its refresh token can no longer be exchanged.
+func (s *UserService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if _, err := s.tokenStore.Get(userID, sessionID); errors.Is(err, jtoken.ErrSessionRevoked) {
+		return ErrSessionNotFound
+	}
+
+	if err := s.tokenStore.Revoke(userID, sessionID); err != nil {
+		logger.Errorf("RevokeSession.Revoke fail, id: %s, error: %s", userID, err)
+		return err
+	}
+
+	return nil
+}
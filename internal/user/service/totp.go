@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/jtoken"
+	"main/pkg/totp"
+	"main/pkg/utils"
+)
+
+var (
+	ErrInvalidMFAToken   = errors.New("invalid or expired mfa token")
+	ErrWrongMFACode      = errors.New("mfa code not correct")
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnrolled    = errors.New("two-factor authentication not enrolled")
+)
+
+// LoginMFA exchanges an MFA token from Login and a TOTP or recovery code for
+// an access/refresh token pair. Wrong codes count as failed logins.
+func (s *UserService) LoginMFA(ctx context.Context, req *dto.LoginMFAReq) (res *dto.LoginRes, err error) {
+	var user *model.User
+	defer func() { s.record(ctx, audit.EventLoginMFA, idOf(user), "", err) }()
+
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	claims, err := jtoken.ValidateToken(req.MFAToken)
+	if err != nil || claims.Type != jtoken.MFATokenType {
+		return nil, ErrInvalidMFAToken
+	}
+
+	user, err = s.repo.GetUserByID(ctx, claims.Subject)
+	if err != nil {
+		logger.Errorf("LoginMFA.GetUserByID fail, id: %s, error: %s", claims.Subject, err)
+		return nil, ErrInvalidMFAToken
+	}
+
+	emailKey := strings.ToLower(user.Email)
+	if err = s.reserveLogin(emailKey, req.IP); err != nil {
+		return nil, err
+	}
+
+	if !user.TOTPEnabled {
+		return nil, ErrInvalidMFAToken
+	}
+
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	ok, err := s.useSecondFactor(ctx, user, req.Code, true)
+	if err != nil {
+		logger.Errorf("LoginMFA.useSecondFactor fail, id: %s, error: %s", user.ID, err)
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrWrongMFACode
+	}
+	s.loginPassed(req.IP)
+
+	return s.startSession(user, emailKey, req.IP, req.UserAgent)
+}
+
+// EnrollTOTP creates a new TOTP secret for the user. It takes effect once a
+// code generated from it is confirmed with ConfirmTOTP.
+func (s *UserService) EnrollTOTP(ctx context.Context, id string) (*dto.EnrollTOTPRes, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("EnrollTOTP.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("EnrollTOTP.Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return &dto.EnrollTOTPRes{
+		Secret: secret,
+		URI:    totp.URI(config.GetConfig().TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables 2FA once the user proves the authenticator app works,
+// and returns recovery codes which are only shown this once.
+func (s *UserService) ConfirmTOTP(ctx context.Context, id string, req *dto.TOTPCodeReq) (*dto.ConfirmTOTPRes, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("ConfirmTOTP.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	ok, err := s.useSecondFactor(ctx, user, req.Code, false)
+	if err != nil {
+		logger.Errorf("ConfirmTOTP.useSecondFactor fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrWrongMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes(config.RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	user.RecoveryCodeHashes = hashes
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("ConfirmTOTP.Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return &dto.ConfirmTOTPRes{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP turns 2FA off after checking a TOTP or recovery code.
+func (s *UserService) DisableTOTP(ctx context.Context, id string, req *dto.TOTPCodeReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("DisableTOTP.GetUserByID fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	if !user.TOTPEnabled {
+		return ErrMFANotEnrolled
+	}
+
+	ok, err := s.useSecondFactor(ctx, user, req.Code, true)
+	if err != nil {
+		logger.Errorf("DisableTOTP.useSecondFactor fail, id: %s, error: %s", id, err)
+		return err
+	}
+	if !ok {
+		return ErrWrongMFACode
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.RecoveryCodeHashes = ""
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("DisableTOTP.Update fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	return nil
+}
+
+// useSecondFactor accepts a TOTP code that has not been used yet or, when
+// allowRecovery is set, an unused recovery code. The use is saved at once and
+// only if no parallel request used the code first, then recorded on user too.
+func (s *UserService) useSecondFactor(ctx context.Context, user *model.User, code string, allowRecovery bool) (bool, error) {
+	code = strings.TrimSpace(code)
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), config.TOTPSkew)
+	if ok && step > user.TOTPLastStep {
+		used, err := s.repo.UseTOTPStep(ctx, user.ID, step)
+		if err != nil || !used {
+			return false, err
+		}
+		user.TOTPLastStep = step
+		return true, nil
+	}
+
+	if !allowRecovery || user.RecoveryCodeHashes == "" {
+		return false, nil
+	}
+
+	hash := utils.HashToken(strings.ToLower(code))
+	hashes := strings.Split(user.RecoveryCodeHashes, ",")
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			remaining := strings.Join(append(hashes[:i], hashes[i+1:]...), ",")
+			used, err := s.repo.UseRecoveryCode(ctx, user.ID, user.RecoveryCodeHashes, remaining)
+			if err != nil || !used {
+				return false, err
+			}
+			user.RecoveryCodeHashes = remaining
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// generateRecoveryCodes returns count codes formatted as xxxxx-xxxxx and the
+// comma separated hashes to store.
+func generateRecoveryCodes(count int) ([]string, string, error) {
+	codes := make([]string, count)
+	hashes := make([]string, count)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, "", err
+		}
+
+		code := hex.EncodeToString(b)
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = utils.HashToken(codes[i])
+	}
+
+	return codes, strings.Join(hashes, ","), nil
+}
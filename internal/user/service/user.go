@@ -2,80 +2,288 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/quangdangfit/gocommon/logger"
 	"github.com/quangdangfit/gocommon/validation"
-	"golang.org/x/crypto/bcrypt"
 
 	"main/internal/user/dto"
 	"main/internal/user/model"
 	"main/internal/user/repository"
+	"main/pkg/apikey"
+	"main/pkg/audit"
+	"main/pkg/config"
 	"main/pkg/jtoken"
+	"main/pkg/mailer"
+	"main/pkg/oidc"
+	"main/pkg/otp"
+	"main/pkg/paging"
+	"main/pkg/password"
+	"main/pkg/redis"
+	"main/pkg/throttle"
 	"main/pkg/utils"
 )
 
+var (
+	ErrEmailNotVerified  = errors.New("email is not verified")
+	ErrAlreadyVerified   = errors.New("user already verified")
+	ErrWrongVerifyCode   = errors.New("verify code not correct")
+	ErrVerifyCodeExpired = errors.New("verify code expired")
+	ErrTooManyAttempts   = errors.New("too many verification attempts")
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	ErrUserDisabled      = errors.New("user is disabled")
+	ErrWrongPassword     = errors.New("wrong password")
+	ErrPhoneTaken        = errors.New("phone already in use")
+)
+
 //go:generate mockery --name=IUserService
 type IUserService interface {
-	Login(ctx context.Context, req *dto.LoginReq) (*model.User, string, string, error)
+	Login(ctx context.Context, req *dto.LoginReq) (*dto.LoginRes, error)
+	LoginMFA(ctx context.Context, req *dto.LoginMFAReq) (*dto.LoginRes, error)
+	RequestOTP(ctx context.Context, req *dto.RequestOTPReq) error
+	VerifyOTP(ctx context.Context, req *dto.VerifyOTPReq) (*dto.LoginRes, error)
 	Register(ctx context.Context, req *dto.RegisterReq) (*model.User, error)
 	GetUserByID(ctx context.Context, id string) (*model.User, error)
-	RefreshToken(ctx context.Context, userID string) (string, error)
+	UpdateProfile(ctx context.Context, id string, req *dto.UpdateProfileReq) (*model.User, error)
+	ExportAccount(ctx context.Context, id string, req *dto.ExportAccountReq) (*dto.ExportFile, error)
+	DeleteAccount(ctx context.Context, id string, req *dto.DeleteAccountReq) error
+	RefreshToken(ctx context.Context, userID, sessionID, tokenID string) (string, string, error)
+	Logout(ctx context.Context, userID, sessionID string) error
 	VerifyUser(ctx context.Context, request dto.VerifyRequest) (dto.VerifyResponse, error)
+	ResendVerifyCode(ctx context.Context, req *dto.ResendVerifyCodeReq) error
 	ChangePassword(ctx context.Context, id string, req *dto.ChangePasswordReq) error
+	ChangeEmail(ctx context.Context, id string, req *dto.ChangeEmailReq) error
+	ConfirmEmailChange(ctx context.Context, id string, req *dto.ConfirmEmailChangeReq) (*model.User, error)
+	ForgotPassword(ctx context.Context, req *dto.ForgotPasswordReq) error
+	ResetPassword(ctx context.Context, req *dto.ResetPasswordReq) error
+	ListUsers(ctx context.Context, req *dto.ListUsersReq) ([]*model.User, *paging.Pagination, error)
+	UpdateRole(ctx context.Context, id string, req *dto.UpdateRoleReq) (*model.User, error)
+	ApproveUser(ctx context.Context, id string) (*model.User, error)
+	SetDisabled(ctx context.Context, id string, disabled bool) (*model.User, error)
+	UnlockUser(ctx context.Context, id string, req *dto.UnlockUserReq) error
+	EnrollTOTP(ctx context.Context, id string) (*dto.EnrollTOTPRes, error)
+	ConfirmTOTP(ctx context.Context, id string, req *dto.TOTPCodeReq) (*dto.ConfirmTOTPRes, error)
+	DisableTOTP(ctx context.Context, id string, req *dto.TOTPCodeReq) error
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]*dto.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	CreateAPIKey(ctx context.Context, adminID string, req *dto.CreateAPIKeyReq) (*dto.CreateAPIKeyRes, error)
+	ListAPIKeys(ctx context.Context) ([]*dto.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	ListAuditEvents(ctx context.Context, req *dto.ListAuditEventsReq) ([]*audit.Event, *paging.Pagination, error)
+	Impersonate(ctx context.Context, adminID, sessionID, id string, req *dto.ImpersonateReq) (*dto.ImpersonateRes, error)
+	OIDCAuthURL(ctx context.Context) (string, error)
+	OIDCLogin(ctx context.Context, req *dto.OIDCLoginReq) (*dto.LoginRes, error)
 }
 
 type UserService struct {
-	validator validation.Validation
-	repo      repository.IUserRepository
+	validator     validation.Validation
+	repo          repository.IUserRepository
+	cache         redis.IRedis
+	tokenStore    jtoken.IStore
+	mailer        mailer.IMailer
+	emailThrottle throttle.IThrottle
+	ipThrottle    throttle.IThrottle
+	otp           otp.IOTP
+	apiKeys       apikey.IStore
+	audit         audit.IAudit
+	// verifyEmailThrottle and verifyIPThrottle limit the verification codes
+	// sent per email and per client IP.
+	verifyEmailThrottle throttle.IThrottle
+	verifyIPThrottle    throttle.IThrottle
+	// oidc is nil when no OpenID Connect provider is configured.
+	oidc oidc.IProvider
 }
 
 func NewUserService(
 	validator validation.Validation,
-	repo repository.IUserRepository) *UserService {
+	repo repository.IUserRepository,
+	cache redis.IRedis,
+	tokenStore jtoken.IStore,
+	mailer mailer.IMailer,
+	emailThrottle throttle.IThrottle,
+	ipThrottle throttle.IThrottle,
+	verifyEmailThrottle throttle.IThrottle,
+	verifyIPThrottle throttle.IThrottle,
+	otp otp.IOTP,
+	apiKeys apikey.IStore,
+	audit audit.IAudit,
+	oidc oidc.IProvider) *UserService {
 	return &UserService{
-		validator: validator,
-		repo:      repo,
+		validator:           validator,
+		repo:                repo,
+		cache:               cache,
+		tokenStore:          tokenStore,
+		mailer:              mailer,
+		emailThrottle:       emailThrottle,
+		ipThrottle:          ipThrottle,
+		verifyEmailThrottle: verifyEmailThrottle,
+		verifyIPThrottle:    verifyIPThrottle,
+		otp:                 otp,
+		apiKeys:             apiKeys,
+		audit:               audit,
+		oidc:                oidc,
 	}
 }
 
-func (s *UserService) Login(ctx context.Context, req *dto.LoginReq) (*model.User, string, string, error) {
+// Login checks the password of a user. Users with 2FA enabled get an MFA
+// token to pass to LoginMFA instead of the token pair.
+func (s *UserService) Login(ctx context.Context, req *dto.LoginReq) (res *dto.LoginRes, err error) {
+	var user *model.User
+	defer func() { s.record(ctx, loginEvent(audit.EventLogin, res), idOf(user), req.Email, err) }()
+
 	if err := s.validator.ValidateStruct(req); err != nil {
-		return nil, "", "", err
+		return nil, err
 	}
 
-	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	emailKey := strings.ToLower(req.Email)
+	if err := s.reserveLogin(emailKey, req.IP); err != nil {
+		return nil, err
+	}
+
+	user, err = s.repo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		logger.Errorf("Login.GetUserByEmail fail, email: %s, error: %s", req.Email, err)
-		return nil, "", "", err
+		return nil, err
+	}
+
+	if err = password.Compare(user.Password, req.Password); err != nil {
+		return nil, ErrWrongPassword
+	}
+
+	s.loginPassed(req.IP)
+	s.rehashPassword(ctx, user, req.Password)
+	return s.completeLogin(user, emailKey, req.IP, req.UserAgent)
+}
+
+// completeLogin checks that a user who proved the first factor may log in.
+// Users with 2FA enabled get an MFA token instead of a session.
+func (s *UserService) completeLogin(user *model.User, emailKey, ip, userAgent string) (*dto.LoginRes, error) {
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	if config.GetConfig().RequireVerifiedEmail && !user.Approve {
+		return nil, ErrEmailNotVerified
+	}
+
+	// The failures are not reset yet, so that the second factor cannot be
+	// guessed by logging in again between attempts.
+	if user.TOTPEnabled {
+		res := &dto.LoginRes{
+			MFARequired: true,
+			MFAToken:    jtoken.GenerateMFAToken(jtoken.Claims{Subject: user.ID}),
+		}
+		utils.Copy(&res.User, user)
+		return res, nil
+	}
+
+	return s.startSession(user, emailKey, ip, userAgent)
+}
+
+// startSession opens a new session on the client's device for a fully
+// authenticated user.
+func (s *UserService) startSession(user *model.User, emailKey, ip, userAgent string) (*dto.LoginRes, error) {
+	session := &jtoken.Session{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		UserAgent: userAgent,
+		IP:        ip,
+		CreatedAt: time.Now(),
+	}
+	accessToken, refreshToken, err := s.issueTokens(user, session)
+	if err != nil {
+		logger.Errorf("Login.issueTokens fail, id: %s, error: %s", user.ID, err)
+		return nil, err
+	}
+
+	// The IP counter is kept, otherwise logging into an own account would
+	// let a client keep guessing passwords of others.
+	if err = s.emailThrottle.Reset(emailKey); err != nil {
+		logger.Errorf("Login.Reset fail, id: %s, error: %s", user.ID, err)
+	}
+
+	res := &dto.LoginRes{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}
+	utils.Copy(&res.User, user)
+	return res, nil
+}
+
+// rehashPassword replaces a hash made with an older algorithm or cost once the
+// password is known. A failure only postpones it to the next login.
+func (s *UserService) rehashPassword(ctx context.Context, user *model.User, plain string) {
+	if !password.NeedsRehash(user.Password) {
+		return
+	}
+
+	hash, err := password.Hash(plain)
+	if err != nil {
+		logger.Errorf("Login.Hash fail, id: %s, error: %s", user.ID, err)
+		return
 	}
 
-	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, "", "", errors.New("wrong password")
+	user.Password = hash
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("Login.Update fail, id: %s, error: %s", user.ID, err)
 	}
+}
+
+// reserveLogin counts a login attempt for the client IP and the email before
+// the credentials are checked, so that parallel guesses cannot all pass before
+// the first failure is recorded. It returns a *throttle.LockedError if either
+// is locked out or still has to wait.
+func (s *UserService) reserveLogin(emailKey, ip string) error {
+	if ip != "" {
+		if err := s.ipThrottle.Reserve(ip); err != nil {
+			return err
+		}
+	}
+	return s.emailThrottle.Reserve(emailKey)
+}
 
-	tokenData := map[string]interface{}{
-		"id":    user.ID,
-		"email": user.Email,
-		"role":  user.Role,
+// loginPassed takes back the attempt reserved for the client IP once the
+// credentials are right. The email counter is reset when the session starts.
+func (s *UserService) loginPassed(ip string) {
+	if ip == "" {
+		return
+	}
+	if err := s.ipThrottle.Release(ip); err != nil {
+		logger.Errorf("Login.Release fail, ip: %s, error: %s", ip, err)
 	}
-	accessToken := jtoken.GenerateAccessToken(tokenData)
-	refreshToken := jtoken.GenerateRefreshToken(tokenData)
-	return user, accessToken, refreshToken, nil
 }
 
-func (s *UserService) Register(ctx context.Context, req *dto.RegisterReq) (*model.User, error) {
+func (s *UserService) Register(ctx context.Context, req *dto.RegisterReq) (created *model.User, err error) {
+	defer func() { s.record(ctx, audit.EventRegister, idOf(created), req.Email, err) }()
+
 	if err := s.validator.ValidateStruct(req); err != nil {
 		return nil, err
 	}
 
 	var user model.User
 	utils.Copy(&user, &req)
-	err := s.repo.Create(ctx, &user)
+	code, err := setVerifyCode(&user)
+	if err != nil {
+		logger.Errorf("Register.setVerifyCode fail, email: %s, error: %s", req.Email, err)
+		return nil, err
+	}
+
+	err = s.repo.Create(ctx, &user)
 	if err != nil {
 		logger.Errorf("Register.Create fail, email: %s, error: %s", req.Email, err)
 		return nil, err
 	}
+
+	// The account exists at this point, a failed mail can be retried through
+	// ResendVerifyCode.
+	if err = s.sendVerifyCode(&user, code); err != nil {
+		logger.Errorf("Register.sendVerifyCode fail, email: %s, error: %s", req.Email, err)
+	}
 	return &user, nil
 }
 
@@ -89,23 +297,146 @@ func (s *UserService) GetUserByID(ctx context.Context, id string) (*model.User,
 	return user, nil
 }
 
-func (s *UserService) RefreshToken(ctx context.Context, userID string) (string, error) {
+// UpdateProfile sets the profile fields present in req.
+func (s *UserService) UpdateProfile(ctx context.Context, id string, req *dto.UpdateProfileReq) (*model.User, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("UpdateProfile.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	if req.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.Phone != nil && *req.Phone != user.Phone {
+		if *req.Phone != "" {
+			exists, err := s.repo.PhoneExists(ctx, *req.Phone)
+			if err != nil {
+				logger.Errorf("UpdateProfile.PhoneExists fail, id: %s, error: %s", id, err)
+				return nil, err
+			}
+			if exists {
+				return nil, ErrPhoneTaken
+			}
+		}
+		user.Phone = *req.Phone
+		user.PhoneVerified = false
+	}
+	if req.AvatarURL != nil {
+		user.AvatarURL = *req.AvatarURL
+	}
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("UpdateProfile.Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// RefreshToken exchanges the refresh token identified by tokenID for a new
+// access/refresh token pair. Presenting a refresh token that was already
+// rotated revokes the whole session, since it means the token has leaked.
+func (s *UserService) RefreshToken(ctx context.Context, userID, sessionID, tokenID string) (_ string, _ string, err error) {
+	defer func() { s.record(ctx, audit.EventTokenRefresh, userID, "", err) }()
+
+	session, err := s.tokenStore.Get(userID, sessionID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if session.TokenID != tokenID {
+		logger.Warnf("RefreshToken reuse detected, revoking session, id: %s, session: %s", userID, sessionID)
+		if err = s.tokenStore.Revoke(userID, sessionID); err != nil {
+			logger.Errorf("RefreshToken.Revoke fail, id: %s, error: %s", userID, err)
+		}
+		return "", "", jtoken.ErrTokenReused
+	}
+
 	user, err := s.repo.GetUserByID(ctx, userID)
 	if err != nil {
 		logger.Errorf("RefreshToken.GetUserByID fail, id: %s, error: %s", userID, err)
-		return "", err
+		return "", "", err
+	}
+
+	if user.Disabled {
+		if err = s.tokenStore.Revoke(userID, sessionID); err != nil {
+			logger.Errorf("RefreshToken.Revoke fail, id: %s, error: %s", userID, err)
+		}
+		return "", "", ErrUserDisabled
+	}
+
+	// A concurrent exchange of the same token may have won since Get, the
+	// swap only succeeds for the first one.
+	session.TokenID = uuid.New().String()
+	session.LastUsedAt = time.Now()
+	if err = s.tokenStore.Rotate(session, tokenID); err != nil {
+		if errors.Is(err, jtoken.ErrTokenReused) {
+			logger.Warnf("RefreshToken reuse detected, revoking session, id: %s, session: %s", userID, sessionID)
+			if revokeErr := s.tokenStore.Revoke(userID, sessionID); revokeErr != nil {
+				logger.Errorf("RefreshToken.Revoke fail, id: %s, error: %s", userID, revokeErr)
+			}
+		}
+		return "", "", err
+	}
+
+	accessToken, refreshToken := signTokens(user, session)
+	return accessToken, refreshToken, nil
+}
+
+func (s *UserService) Logout(ctx context.Context, userID, sessionID string) error {
+	err := s.tokenStore.Revoke(userID, sessionID)
+	s.record(ctx, audit.EventLogout, userID, "", err)
+	if err != nil {
+		logger.Errorf("Logout.Revoke fail, id: %s, error: %s", userID, err)
+		return err
 	}
 
-	tokenData := map[string]interface{}{
-		"id":    user.ID,
-		"email": user.Email,
-		"role":  user.Role,
+	return nil
+}
+
+// issueTokens gives a new session its first refresh token id and signs a
+// token pair bound to it.
+func (s *UserService) issueTokens(user *model.User, session *jtoken.Session) (string, string, error) {
+	session.TokenID = uuid.New().String()
+	session.LastUsedAt = time.Now()
+	if err := s.tokenStore.Save(session); err != nil {
+		return "", "", err
 	}
-	accessToken := jtoken.GenerateAccessToken(tokenData)
-	return accessToken, nil
+
+	accessToken, refreshToken := signTokens(user, session)
+	return accessToken, refreshToken, nil
+}
+
+// signTokens signs a token pair bound to the current refresh token id of the
+// session.
+func signTokens(user *model.User, session *jtoken.Session) (string, string) {
+	accessToken := jtoken.GenerateAccessToken(jtoken.Claims{
+		Subject:   user.ID,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		SessionID: session.ID,
+	})
+	refreshToken := jtoken.GenerateRefreshToken(jtoken.Claims{
+		Subject:   user.ID,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		SessionID: session.ID,
+		ID:        session.TokenID,
+	})
+	return accessToken, refreshToken
 }
 
-func (s *UserService) ChangePassword(ctx context.Context, id string, req *dto.ChangePasswordReq) error {
+func (s *UserService) ChangePassword(ctx context.Context, id string, req *dto.ChangePasswordReq) (err error) {
+	defer func() { s.record(ctx, audit.EventPasswordChange, id, "", err) }()
+
 	if err := s.validator.ValidateStruct(req); err != nil {
 		return err
 	}
@@ -115,11 +446,16 @@ func (s *UserService) ChangePassword(ctx context.Context, id string, req *dto.Ch
 		return err
 	}
 
-	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return errors.New("wrong password")
+	if err = password.Compare(user.Password, req.Password); err != nil {
+		return ErrWrongPassword
+	}
+
+	user.Password, err = password.Hash(req.NewPassword)
+	if err != nil {
+		logger.Errorf("ChangePassword.Hash fail, id: %s, error: %s", id, err)
+		return err
 	}
 
-	user.Password = utils.HashAndSalt([]byte(req.NewPassword))
 	err = s.repo.Update(ctx, user)
 	if err != nil {
 		logger.Errorf("ChangePassword.Update fail, id: %s, error: %s", id, err)
@@ -129,20 +465,328 @@ func (s *UserService) ChangePassword(ctx context.Context, id string, req *dto.Ch
 	return nil
 }
 
-func (s *UserService) VerifyUser(ctx context.Context, request dto.VerifyRequest) (dto.VerifyResponse, error) {
-	user, err := s.repo.FindByEmailAndVerifyCode(ctx, request.Email, request.VerifyCode)
+// ForgotPassword mails a single-use reset token to the user. Unknown emails
+// are not reported so that the endpoint cannot be used to discover accounts.
+func (s *UserService) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		logger.Infof("ForgotPassword.GetUserByEmail fail, email: %s, error: %s", req.Email, err)
+		return nil
+	}
+
+	token, err := utils.GenerateToken(config.ResetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(config.ResetTokenExpiredTime)
+	user.ResetTokenHash = utils.HashToken(token)
+	user.ResetTokenExpiresAt = &expiresAt
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("ForgotPassword.Update fail, email: %s, error: %s", req.Email, err)
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Use this token to reset your password: %s\nIt expires in %d minutes. If you did not ask for a reset you can ignore this email.",
+		token,
+		int(config.ResetTokenExpiredTime.Minutes()),
+	)
+	return s.mailer.Send(user.Email, "Reset your password", body)
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword. The
+// token is consumed and every session of the user is revoked.
+func (s *UserService) ResetPassword(ctx context.Context, req *dto.ResetPasswordReq) (err error) {
+	var user *model.User
+	defer func() { s.record(ctx, audit.EventPasswordReset, idOf(user), "", err) }()
+
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	user, err = s.repo.GetUserByResetTokenHash(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	if user.ResetTokenExpiresAt == nil || time.Now().After(*user.ResetTokenExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user.Password, err = password.Hash(req.NewPassword)
+	if err != nil {
+		logger.Errorf("ResetPassword.Hash fail, id: %s, error: %s", user.ID, err)
+		return err
+	}
+
+	user.ResetTokenHash = ""
+	user.ResetTokenExpiresAt = nil
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("ResetPassword.Update fail, id: %s, error: %s", user.ID, err)
+		return err
+	}
+
+	if err = s.tokenStore.RevokeAll(user.ID); err != nil {
+		logger.Errorf("ResetPassword.RevokeAll fail, id: %s, error: %s", user.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context, req *dto.ListUsersReq) ([]*model.User, *paging.Pagination, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, nil, err
+	}
+
+	users, pagination, err := s.repo.ListUsers(ctx, req)
+	if err != nil {
+		logger.Errorf("ListUsers fail, error: %s", err)
+		return nil, nil, err
+	}
+
+	return users, pagination, nil
+}
+
+// UpdateRole changes the role of a user. Tokens already issued keep the old
+// role until they are refreshed, so the user's sessions are revoked. API keys
+// of the user take the new role.
+func (s *UserService) UpdateRole(ctx context.Context, id string, req *dto.UpdateRoleReq) (*model.User, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("UpdateRole.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	user.Role = model.UserRole(req.Role)
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("UpdateRole.Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	if err = s.tokenStore.RevokeAll(user.ID); err != nil {
+		logger.Errorf("UpdateRole.RevokeAll fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	if err = s.apiKeys.UpdateRole(ctx, user.ID, req.Role); err != nil {
+		logger.Errorf("UpdateRole.UpdateRole fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ApproveUser marks the email of a user as verified without a code.
+func (s *UserService) ApproveUser(ctx context.Context, id string) (*model.User, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("ApproveUser.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	user.Approve = true
+	user.VerifyCode = ""
+	user.VerifyCodeExpiresAt = nil
+	user.VerifyAttempts = 0
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("ApproveUser.Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetDisabled disables or re-enables a user. Disabling revokes every session
+// and API key of the user, so tokens that have not expired yet are rejected as
+// well.
+func (s *UserService) SetDisabled(ctx context.Context, id string, disabled bool) (*model.User, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("SetDisabled.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	user.Disabled = disabled
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("SetDisabled.Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	if disabled {
+		if err = s.tokenStore.RevokeAll(user.ID); err != nil {
+			logger.Errorf("SetDisabled.RevokeAll fail, id: %s, error: %s", id, err)
+			return nil, err
+		}
+
+		if err = s.apiKeys.RevokeAll(ctx, user.ID); err != nil {
+			logger.Errorf("SetDisabled.RevokeAll keys fail, id: %s, error: %s", id, err)
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// UnlockUser lifts the login lockout and delays of a user before they expire.
+// Lockouts are kept per email and per client IP, the lockout of the IP in req
+// is lifted as well.
+func (s *UserService) UnlockUser(ctx context.Context, id string, req *dto.UnlockUserReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("UnlockUser.GetUserByID fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	if err = s.emailThrottle.Reset(strings.ToLower(user.Email)); err != nil {
+		logger.Errorf("UnlockUser.Reset fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	if req.IP != "" {
+		if err = s.ipThrottle.Reset(req.IP); err != nil {
+			logger.Errorf("UnlockUser.Reset fail, ip: %s, error: %s", req.IP, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *UserService) VerifyUser(ctx context.Context, request dto.VerifyRequest) (_ dto.VerifyResponse, err error) {
+	var user *model.User
+	defer func() { s.record(ctx, audit.EventVerifyEmail, idOf(user), request.Email, err) }()
+
+	if err := s.validator.ValidateStruct(request); err != nil {
+		return dto.VerifyResponse{Message: "Verification failed"}, err
+	}
+
+	user, err = s.repo.GetUserByEmail(ctx, request.Email)
 	if err != nil {
 		return dto.VerifyResponse{Message: "Verification failed"}, err
 	}
 
-	if user == nil {
-		return dto.VerifyResponse{Message: "Verify code not correct"}, errors.New("verify code not correct")
+	if user.Approve {
+		return dto.VerifyResponse{Message: "User already verified"}, ErrAlreadyVerified
+	}
+
+	if user.VerifyCode == "" || user.VerifyCodeExpiresAt == nil || time.Now().After(*user.VerifyCodeExpiresAt) {
+		return dto.VerifyResponse{Message: "Verify code expired, request a new code"}, ErrVerifyCodeExpired
+	}
+
+	// The attempt is counted before the code is compared, so that parallel
+	// guesses cannot exceed the limit.
+	counted, err := s.repo.CountVerifyAttempt(ctx, user.ID, config.VerifyCodeMaxAttempts)
+	if err != nil {
+		return dto.VerifyResponse{Message: "Failed to update user"}, err
+	}
+	if !counted {
+		return dto.VerifyResponse{Message: "Too many attempts, request a new code"}, ErrTooManyAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(user.VerifyCode), []byte(utils.HashToken(request.VerifyCode))) != 1 {
+		return dto.VerifyResponse{Message: "Verify code not correct"}, ErrWrongVerifyCode
 	}
 
 	user.Approve = true
-	if err := s.repo.UpdateUser(ctx, user); err != nil {
+	user.VerifyCode = ""
+	user.VerifyCodeExpiresAt = nil
+	user.VerifyAttempts = 0
+	if err := s.repo.Update(ctx, user); err != nil {
 		return dto.VerifyResponse{Message: "Failed to update user"}, err
 	}
 
 	return dto.VerifyResponse{Message: "Verification successful"}, nil
 }
+
+// ResendVerifyCode mails a new verification code. Unknown and already verified
+// emails are not reported so that the endpoint cannot be used to discover
+// accounts. It
+// returns a *throttle.LockedError while the email or the client IP may not
+// request another code yet.
+func (s *UserService) ResendVerifyCode(ctx context.Context, req *dto.ResendVerifyCodeReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	email := strings.ToLower(req.Email)
+	if err := s.verifyEmailThrottle.Check(email); err != nil {
+		return err
+	}
+	if req.IP != "" {
+		if err := s.verifyIPThrottle.Check(req.IP); err != nil {
+			return err
+		}
+	}
+
+	// Every request counts, so that resending waits longer each time.
+	if err := s.verifyEmailThrottle.Fail(email); err != nil {
+		return err
+	}
+	if req.IP != "" {
+		if err := s.verifyIPThrottle.Fail(req.IP); err != nil {
+			return err
+		}
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		logger.Infof("ResendVerifyCode.GetUserByEmail fail, email: %s, error: %s", req.Email, err)
+		return nil
+	}
+
+	if user.Approve {
+		logger.Infof("ResendVerifyCode user already verified, email: %s", req.Email)
+		return nil
+	}
+
+	code, err := setVerifyCode(user)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("ResendVerifyCode.Update fail, email: %s, error: %s", req.Email, err)
+		return err
+	}
+
+	return s.sendVerifyCode(user, code)
+}
+
+// setVerifyCode gives the user a fresh verification code, resets the expiry
+// and the attempt counter, and returns the code to mail. Only its hash is
+// stored.
+func setVerifyCode(user *model.User) (string, error) {
+	code, err := utils.GenerateNumericCode(config.VerifyCodeLength)
+	if err != nil {
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(config.VerifyCodeExpiredTime)
+	user.VerifyCode = utils.HashToken(code)
+	user.VerifyCodeExpiresAt = &expiresAt
+	user.VerifyAttempts = 0
+	return code, nil
+}
+
+func (s *UserService) sendVerifyCode(user *model.User, code string) error {
+	body := fmt.Sprintf(
+		"Your verification code is %s. It expires in %d minutes.",
+		code,
+		int(config.VerifyCodeExpiredTime.Minutes()),
+	)
+	return s.mailer.Send(user.Email, "Verify your email", body)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"github.com/quangdangfit/gocommon/validation"
+
+	"main/internal/user/repository"
+	"main/pkg/apikey"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/jtoken"
+	"main/pkg/mailer"
+	"main/pkg/oidc"
+	"main/pkg/otp"
+	"main/pkg/redis"
+	"main/pkg/sms"
+	"main/pkg/throttle"
+)
+
+// NewFromConfig builds the UserService and its dependencies from cfg, as used
+// by both the HTTP and the gRPC port.
+func NewFromConfig(cfg *config.Schema, db dbs.IDatabase, cache redis.IRedis, validator validation.Validation) *UserService {
+	emailThrottle := throttle.New(cache, "login:email", throttle.Policy{
+		MaxAttempts: cfg.LoginMaxAttempts,
+		Delay:       cfg.LoginDelay,
+		Lockout:     cfg.LoginLockoutTime,
+	})
+	ipThrottle := throttle.New(cache, "login:ip", throttle.Policy{
+		MaxAttempts: cfg.LoginMaxAttemptsPerIP,
+		Lockout:     cfg.LoginLockoutTime,
+	})
+	verifyEmailThrottle := throttle.New(cache, "verify:email", throttle.Policy{
+		MaxAttempts: cfg.VerifyCodeMaxSends,
+		Delay:       cfg.VerifyCodeResendDelay,
+		Lockout:     cfg.VerifyCodeSendWindow,
+	})
+	verifyIPThrottle := throttle.New(cache, "verify:ip", throttle.Policy{
+		MaxAttempts: cfg.VerifyCodeMaxSendsPerIP,
+		Lockout:     cfg.VerifyCodeSendWindow,
+	})
+	loginOTP := otp.New(cache, sms.New(cfg),
+		throttle.New(cache, "otp:phone", throttle.Policy{
+			MaxAttempts: cfg.OTPMaxSends,
+			Delay:       cfg.OTPResendDelay,
+			Lockout:     cfg.OTPSendWindow,
+		}),
+		throttle.New(cache, "otp:ip", throttle.Policy{
+			MaxAttempts: cfg.OTPMaxSendsPerIP,
+			Lockout:     cfg.OTPSendWindow,
+		}),
+		throttle.New(cache, "otp:verify:ip", throttle.Policy{
+			MaxAttempts: cfg.OTPMaxFailuresPerIP,
+			Lockout:     cfg.OTPSendWindow,
+		}),
+		otp.Policy{
+			Length:      config.OTPLength,
+			ExpiresIn:   config.OTPExpiredTime,
+			MaxAttempts: config.OTPMaxAttempts,
+		},
+	)
+
+	return NewUserService(
+		validator,
+		repository.NewUserRepository(db),
+		cache,
+		jtoken.NewStore(cache),
+		mailer.New(cfg),
+		emailThrottle,
+		ipThrottle,
+		verifyEmailThrottle,
+		verifyIPThrottle,
+		loginOTP,
+		apikey.NewStore(db),
+		audit.New(db),
+		oidc.NewFromConfig(cfg, cache),
+	)
+}
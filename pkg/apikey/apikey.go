@@ -0,0 +1,175 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/utils"
+)
+
+// Prefix starts every key so that leaked keys are easy to recognise.
+const Prefix = "ak_"
+
+// Scopes limit what a key may do on top of the role of the user it acts as.
+const (
+	ScopeUsersRead      = "users:read"
+	ScopeUsersWrite     = "users:write"
+	ScopeAddressesRead  = "addresses:read"
+	ScopeAddressesWrite = "addresses:write"
+	ScopeAuditRead      = "audit:read"
+)
+
+// Scopes lists every scope a key can be given.
+var Scopes = []string{
+	ScopeUsersRead,
+	ScopeUsersWrite,
+	ScopeAddressesRead,
+	ScopeAddressesWrite,
+	ScopeAuditRead,
+}
+
+var (
+	ErrInvalidKey  = errors.New("invalid api key")
+	ErrKeyNotFound = errors.New("api key not found")
+)
+
+// Key is an API key used by machine clients instead of a login. Only the
+// sha256 of the key is stored, Hint keeps its first characters so that admins
+// can tell keys apart.
+type Key struct {
+	ID         string     `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Name       string     `json:"name"`
+	Hint       string     `json:"hint"`
+	Hash       string     `json:"-" gorm:"uniqueIndex;not null"`
+	UserID     string     `json:"user_id" gorm:"index"`
+	Role       string     `json:"role"`
+	Scopes     []string   `json:"scopes" gorm:"serializer:json"`
+	CreatedBy  string     `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+func (Key) TableName() string {
+	return "api_keys"
+}
+
+//go:generate mockery --name=IStore
+type IStore interface {
+	Create(ctx context.Context, key *Key) (string, error)
+	List(ctx context.Context) ([]*Key, error)
+	Revoke(ctx context.Context, id string) error
+	RevokeAll(ctx context.Context, userID string) error
+	UpdateRole(ctx context.Context, userID, role string) error
+	Authenticate(ctx context.Context, raw string) (*Key, error)
+}
+
+// Store keeps API keys in the database.
+type Store struct {
+	db dbs.IDatabase
+}
+
+func NewStore(db dbs.IDatabase) *Store {
+	return &Store{db: db}
+}
+
+// Create generates a new secret for key, saves key and returns the secret.
+// The secret cannot be recovered afterwards.
+func (s *Store) Create(ctx context.Context, key *Key) (string, error) {
+	token, err := utils.GenerateToken(config.APIKeySize)
+	if err != nil {
+		return "", err
+	}
+	raw := Prefix + token
+
+	key.ID = uuid.New().String()
+	key.Hint = raw[:len(Prefix)+config.APIKeyHintLength]
+	key.Hash = utils.HashToken(raw)
+	if err := s.db.Create(ctx, key); err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// List returns every key, revoked ones included, newest first.
+func (s *Store) List(ctx context.Context) ([]*Key, error) {
+	var keys []*Key
+	if err := s.db.Find(ctx, &keys, dbs.WithOrder("created_at DESC")); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *Store) Revoke(ctx context.Context, id string) error {
+	result := s.db.GetDB().WithContext(ctx).Model(&Key{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// RevokeAll revokes the keys that act as userID, for example when the user
+// is disabled or deleted.
+func (s *Store) RevokeAll(ctx context.Context, userID string) error {
+	return s.db.GetDB().WithContext(ctx).Model(&Key{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// UpdateRole keeps the role of the keys of userID in line with the user.
+func (s *Store) UpdateRole(ctx context.Context, userID, role string) error {
+	return s.db.GetDB().WithContext(ctx).Model(&Key{}).
+		Where("user_id = ?", userID).
+		Update("role", role).Error
+}
+
+// Authenticate returns the key matching raw if it is neither revoked nor
+// expired, and records that it was used.
+func (s *Store) Authenticate(ctx context.Context, raw string) (*Key, error) {
+	if !strings.HasPrefix(raw, Prefix) {
+		return nil, ErrInvalidKey
+	}
+
+	var key Key
+	query := dbs.NewQuery("hash = ?", utils.HashToken(raw))
+	if err := s.db.FindOne(ctx, &key, dbs.WithQuery(query)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)) {
+		return nil, ErrInvalidKey
+	}
+
+	// Last use is only written once per interval so that busy clients do not
+	// turn every request into a write.
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= config.APIKeyTouchInterval {
+		err := s.db.GetDB().WithContext(ctx).Model(&key).UpdateColumn("last_used_at", now).Error
+		if err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,216 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"gorm.io/gorm"
+
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+)
+
+// Event types. EventMFAChallenge is a correct first factor of a user who
+// still has to pass LoginMFA.
+const (
+	EventLogin          = "login"
+	EventLoginOTP       = "login_otp"
+	EventLoginMFA       = "login_mfa"
+	EventLoginOIDC      = "login_oidc"
+	EventMFAChallenge   = "mfa_challenge"
+	EventRegister       = "register"
+	EventVerifyEmail    = "verify_email"
+	EventPasswordChange = "password_change"
+	EventPasswordReset  = "password_reset"
+	EventTokenRefresh   = "token_refresh"
+	EventLogout         = "logout"
+
+	EventImpersonationStart = "impersonation_start"
+	EventImpersonated       = "impersonated_request"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+// Event is a security relevant action. UserID is empty when the action
+// failed before the user was known, Email then holds what the client sent.
+// ActorID is the admin acting as UserID during an impersonation, Resource the
+// route or method that was called.
+type Event struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+	Type      string    `json:"type" gorm:"index"`
+	Outcome   string    `json:"outcome"`
+	Reason    string    `json:"reason"`
+	UserID    string    `json:"user_id" gorm:"index"`
+	Email     string    `json:"email"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Transport string    `json:"transport"`
+	ActorID   string    `json:"actor_id" gorm:"index"`
+	Resource  string    `json:"resource"`
+}
+
+func (Event) TableName() string {
+	return "audit_events"
+}
+
+func (event *Event) BeforeCreate(tx *gorm.DB) error {
+	event.ID = uuid.New().String()
+	return nil
+}
+
+// Filter selects events for List. Empty fields match every event.
+type Filter struct {
+	UserID    string
+	ActorID   string
+	Email     string
+	Type      string
+	Outcome   string
+	Transport string
+	IP        string
+	From      *time.Time
+	To        *time.Time
+	Page      int64
+	Limit     int64
+}
+
+//go:generate mockery --name=IAudit
+type IAudit interface {
+	Record(ctx context.Context, event *Event)
+	List(ctx context.Context, filter *Filter) ([]*Event, *paging.Pagination, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Log keeps audit events in the database.
+type Log struct {
+	db dbs.IDatabase
+}
+
+func New(db dbs.IDatabase) *Log {
+	return &Log{db: db}
+}
+
+// Record saves event with the client of ctx. Failures are only logged so that
+// auditing never fails the request being audited.
+func (l *Log) Record(ctx context.Context, event *Event) {
+	client := ClientFrom(ctx)
+	event.IP = client.IP
+	event.UserAgent = client.UserAgent
+	event.Transport = client.Transport
+
+	if err := l.db.Create(ctx, event); err != nil {
+		logger.Errorf("Audit.Record fail, type: %s, user: %s, error: %s", event.Type, event.UserID, err)
+	}
+}
+
+// List returns the events matching filter, newest first.
+func (l *Log) List(ctx context.Context, filter *Filter) ([]*Event, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+	defer cancel()
+
+	query := make([]dbs.Query, 0)
+	if filter.UserID != "" {
+		query = append(query, dbs.NewQuery("user_id = ?", filter.UserID))
+	}
+	if filter.ActorID != "" {
+		query = append(query, dbs.NewQuery("actor_id = ?", filter.ActorID))
+	}
+	if filter.Email != "" {
+		query = append(query, dbs.NewQuery("email LIKE ?", "%"+filter.Email+"%"))
+	}
+	if filter.Type != "" {
+		query = append(query, dbs.NewQuery("type = ?", filter.Type))
+	}
+	if filter.Outcome != "" {
+		query = append(query, dbs.NewQuery("outcome = ?", filter.Outcome))
+	}
+	if filter.Transport != "" {
+		query = append(query, dbs.NewQuery("transport = ?", filter.Transport))
+	}
+	if filter.IP != "" {
+		query = append(query, dbs.NewQuery("ip = ?", filter.IP))
+	}
+	if filter.From != nil {
+		query = append(query, dbs.NewQuery("created_at >= ?", *filter.From))
+	}
+	if filter.To != nil {
+		query = append(query, dbs.NewQuery("created_at <= ?", *filter.To))
+	}
+
+	var total int64
+	if err := l.db.Count(ctx, &Event{}, &total, dbs.WithQuery(query...)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(filter.Page, filter.Limit, total)
+
+	var events []*Event
+	if err := l.db.Find(
+		ctx,
+		&events,
+		dbs.WithQuery(query...),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("created_at DESC"),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return events, pagination, nil
+}
+
+// Purge removes the events created before before.
+func (l *Log) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := l.db.GetDB().WithContext(ctx).Where("created_at < ?", before).Delete(&Event{})
+	return result.RowsAffected, result.Error
+}
+
+// Client is where a request came from.
+type Client struct {
+	IP        string
+	UserAgent string
+	Transport string
+}
+
+// ClientFrom returns the client of ctx, which can be a *gin.Context or the
+// context of a gRPC handler.
+func ClientFrom(ctx context.Context) Client {
+	if c, ok := ctx.(*gin.Context); ok {
+		return Client{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			Transport: TransportHTTP,
+		}
+	}
+
+	var client Client
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		client.Transport = TransportGRPC
+		client.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(client.IP); err == nil {
+			client.IP = host
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		client.Transport = TransportGRPC
+		if values := md.Get("user-agent"); len(values) > 0 {
+			client.UserAgent = values[0]
+		}
+	}
+	return client
+}
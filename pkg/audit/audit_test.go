@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientFrom(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("http", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest(http.MethodPost, "/", nil)
+		c.Request.RemoteAddr = "10.0.0.1:4321"
+		c.Request.Header.Set("User-Agent", "curl/8.0")
+
+		assert.Equal(t, Client{IP: "10.0.0.1", UserAgent: "curl/8.0", Transport: TransportHTTP}, ClientFrom(c))
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 4321},
+		})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", "grpc-go/1.62"))
+
+		assert.Equal(t, Client{IP: "10.0.0.2", UserAgent: "grpc-go/1.62", Transport: TransportGRPC}, ClientFrom(ctx))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		assert.Equal(t, Client{}, ClientFrom(context.Background()))
+	})
+}
@@ -16,11 +16,110 @@ const (
 	DatabaseTimeout    = 5 * time.Second
 	ProductCachingTime = 1 * time.Minute
 	AddressCachingTime = 1 * time.Minute
+
+	// GeocodeCachingTime is how long geocoding answers are cached, and
+	// GeocodeReverseMaxDistanceKm how far from a pin a place may be to be
+	// reported for it.
+	GeocodeCachingTime          = 24 * time.Hour
+	GeocodeReverseMaxDistanceKm = 1.0
+
+	VerifyCodeLength      = 6
+	VerifyCodeExpiredTime = 15 * time.Minute
+	VerifyCodeMaxAttempts = 5
+
+	ResetTokenSize        = 32
+	ResetTokenExpiredTime = 1 * time.Hour
+
+	// TOTPSkew is the number of 30 second steps a TOTP code may be off.
+	TOTPSkew          = 1
+	RecoveryCodeCount = 10
+
+	OTPLength      = 6
+	OTPExpiredTime = 5 * time.Minute
+	OTPMaxAttempts = 5
+
+	AccountPurgeInterval = 1 * time.Hour
+	AuditPurgeInterval   = 1 * time.Hour
+
+	APIKeySize       = 32
+	APIKeyHintLength = 8
+	// APIKeyTouchInterval is how often the last use of an API key is saved.
+	APIKeyTouchInterval = 1 * time.Minute
+
+	OIDCTokenSize        = 32
+	OIDCStateExpiredTime = 10 * time.Minute
+	OIDCHTTPTimeout      = 10 * time.Second
+	// OIDCKeysRefreshInterval is how often the signing keys of the identity
+	// provider may be fetched again for an unknown key id.
+	OIDCKeysRefreshInterval = 1 * time.Minute
+
+	CSRFTokenSize = 32
 )
 
 var AuthIgnoreMethods = []string{
 	"/user.UserService/Login",
 	"/user.UserService/Register",
+	"/user.UserService/VerifyUser",
+	"/user.UserService/ResendVerifyCode",
+	"/user.UserService/ForgotPassword",
+	"/user.UserService/ResetPassword",
+	"/user.UserService/LoginMFA",
+	"/user.UserService/RequestOTP",
+	"/user.UserService/VerifyOTP",
+	"/user.UserService/OIDCAuthorize",
+	"/user.UserService/OIDCLogin",
+}
+
+// AuthRefreshMethods are authenticated with a refresh token instead of an
+// access token.
+var AuthRefreshMethods = []string{
+	"/user.UserService/RefreshToken",
+}
+
+// AuthReadOnlyMethods only read data. They are the only methods a read-only
+// impersonation may call.
+var AuthReadOnlyMethods = []string{
+	"/user.UserService/GetMe",
+	"/user.UserService/ExportAccount",
+	"/user.UserService/ListSessions",
+	"/address.AddressService/GetAddressByID",
+	"/address.AddressService/ListAddresses",
+	"/address.AddressService/GetDefaultAddress",
+	"/address.AddressService/ListNearbyAddresses",
+}
+
+// AuthMethodRoles lists the roles allowed to call a gRPC method. Methods that
+// are not listed are open to every authenticated user.
+var AuthMethodRoles = map[string][]string{
+	"/user.UserService/ListUsers":       {"admin"},
+	"/user.UserService/UpdateUserRole":  {"admin"},
+	"/user.UserService/ApproveUser":     {"admin"},
+	"/user.UserService/SetUserDisabled": {"admin"},
+	"/user.UserService/UnlockUser":      {"admin"},
+	"/user.UserService/CreateAPIKey":    {"admin"},
+	"/user.UserService/ListAPIKeys":     {"admin"},
+	"/user.UserService/RevokeAPIKey":    {"admin"},
+	"/user.UserService/ListAuditEvents": {"admin"},
+	"/user.UserService/ImpersonateUser": {"admin"},
+}
+
+// AuthMethodScopes lists the scope an API key needs to call a gRPC method.
+// API keys are refused on methods that are not listed.
+var AuthMethodScopes = map[string]string{
+	"/user.UserService/ListUsers":                 "users:read",
+	"/user.UserService/ListAuditEvents":           "audit:read",
+	"/user.UserService/UpdateUserRole":            "users:write",
+	"/user.UserService/ApproveUser":               "users:write",
+	"/user.UserService/SetUserDisabled":           "users:write",
+	"/user.UserService/UnlockUser":                "users:write",
+	"/address.AddressService/CreateAddress":       "addresses:write",
+	"/address.AddressService/UpdateAddress":       "addresses:write",
+	"/address.AddressService/DeleteAddress":       "addresses:write",
+	"/address.AddressService/SetDefaultAddress":   "addresses:write",
+	"/address.AddressService/GetAddressByID":      "addresses:read",
+	"/address.AddressService/ListAddresses":       "addresses:read",
+	"/address.AddressService/GetDefaultAddress":   "addresses:read",
+	"/address.AddressService/ListNearbyAddresses": "addresses:read",
 }
 
 type Schema struct {
@@ -32,6 +131,105 @@ type Schema struct {
 	RedisURI      string `env:"redis_uri"`
 	RedisPassword string `env:"redis_password"`
 	RedisDB       int    `env:"redis_db"`
+
+	// JWTSigningKeyFile is a PEM RSA or Ed25519 private key used to sign
+	// tokens with RS256 or EdDSA. Tokens are signed with HS256 and AuthSecret
+	// when it is empty.
+	JWTSigningKeyFile string `env:"jwt_signing_key_file"`
+	// JWTVerifyKeyFiles are PEM public keys that are still accepted, such as
+	// the previous signing key while tokens signed with it expire.
+	JWTVerifyKeyFiles []string `env:"jwt_verify_key_files" envSeparator:","`
+	// JWTIssuer and JWTAudience are written to new tokens and, when set,
+	// required on validated tokens.
+	JWTIssuer   string   `env:"jwt_issuer"`
+	JWTAudience []string `env:"jwt_audience" envSeparator:","`
+	// JWTClockSkew is the leeway allowed on exp, nbf and iat.
+	JWTClockSkew time.Duration `env:"jwt_clock_skew" envDefault:"30s"`
+
+	// RequireVerifiedEmail makes Login reject users that have not verified
+	// their email address yet.
+	RequireVerifiedEmail bool   `env:"require_verified_email"`
+	MailDriver           string `env:"mail_driver" envDefault:"log"`
+	MailLogFile          string `env:"mail_log_file"`
+	MailFrom             string `env:"mail_from"`
+	SMTPHost             string `env:"smtp_host"`
+	SMTPPort             int    `env:"smtp_port" envDefault:"587"`
+	SMTPUsername         string `env:"smtp_username"`
+	SMTPPassword         string `env:"smtp_password"`
+	// SMSLogFile receives the text messages, such as login codes, that the
+	// local SMS sender would deliver.
+	SMSLogFile string `env:"sms_log_file"`
+
+	// Failed logins are counted per email and per client IP. An email waits
+	// LoginDelay after its second failure, doubled on every further one, and
+	// both are locked out for LoginLockoutTime once they reach their limit.
+	LoginMaxAttempts      int           `env:"login_max_attempts" envDefault:"5"`
+	LoginMaxAttemptsPerIP int           `env:"login_max_attempts_per_ip" envDefault:"20"`
+	LoginDelay            time.Duration `env:"login_delay" envDefault:"1s"`
+	LoginLockoutTime      time.Duration `env:"login_lockout_time" envDefault:"15m"`
+
+	// Login codes sent by SMS. A phone waits OTPResendDelay after its second
+	// code, doubled on every further one, and phones and client IPs may not
+	// request more than their limit within OTPSendWindow. Client IPs that
+	// enter more than OTPMaxFailuresPerIP wrong codes within OTPSendWindow
+	// are locked out for the rest of it.
+	OTPMaxSends         int           `env:"otp_max_sends" envDefault:"5"`
+	OTPMaxSendsPerIP    int           `env:"otp_max_sends_per_ip" envDefault:"20"`
+	OTPMaxFailuresPerIP int           `env:"otp_max_failures_per_ip" envDefault:"20"`
+	OTPResendDelay      time.Duration `env:"otp_resend_delay" envDefault:"30s"`
+	OTPSendWindow       time.Duration `env:"otp_send_window" envDefault:"1h"`
+
+	// Email verification codes, throttled like login codes.
+	VerifyCodeMaxSends      int           `env:"verify_code_max_sends" envDefault:"5"`
+	VerifyCodeMaxSendsPerIP int           `env:"verify_code_max_sends_per_ip" envDefault:"20"`
+	VerifyCodeResendDelay   time.Duration `env:"verify_code_resend_delay" envDefault:"30s"`
+	VerifyCodeSendWindow    time.Duration `env:"verify_code_send_window" envDefault:"1h"`
+
+	// AccountDeletionGracePeriod is how long a deleted account is kept,
+	// hidden, before it and its addresses are removed for good.
+	AccountDeletionGracePeriod time.Duration `env:"account_deletion_grace_period" envDefault:"720h"`
+
+	// AuditRetention is how long authentication audit events are kept. They
+	// are kept forever when it is 0.
+	AuditRetention time.Duration `env:"audit_retention" envDefault:"2160h"`
+
+	// AuthCookies makes the HTTP API give browsers the refresh token, and the
+	// access token when AuthCookieAccessToken is set, as HttpOnly cookies
+	// instead of in the response body. Requests authenticated by cookie that
+	// change something must echo the csrf_token cookie in the X-CSRF-Token
+	// header. AuthCookieSameSite is strict, lax or none.
+	AuthCookies           bool   `env:"auth_cookies"`
+	AuthCookieAccessToken bool   `env:"auth_cookie_access_token"`
+	AuthCookieDomain      string `env:"auth_cookie_domain"`
+	AuthCookieSecure      bool   `env:"auth_cookie_secure" envDefault:"true"`
+	AuthCookieSameSite    string `env:"auth_cookie_same_site" envDefault:"lax"`
+
+	// Sign in with an OpenID Connect provider such as Google or Apple. It is
+	// disabled when OIDCIssuer is empty. OIDCRedirectURL is the callback the
+	// provider sends users back to and must be registered with it.
+	OIDCIssuer       string   `env:"oidc_issuer"`
+	OIDCClientID     string   `env:"oidc_client_id"`
+	OIDCClientSecret string   `env:"oidc_client_secret"`
+	OIDCRedirectURL  string   `env:"oidc_redirect_url"`
+	OIDCScopes       []string `env:"oidc_scopes" envSeparator:"," envDefault:"openid,email,profile"`
+
+	// GeocoderGazetteerFile is the CSV list of places used to fill in the
+	// coordinates of addresses sent without them, and the city and street of
+	// addresses sent with only coordinates. Geocoding is disabled when it is
+	// empty.
+	GeocoderGazetteerFile string `env:"geocoder_gazetteer_file"`
+
+	// TOTPIssuer is the account issuer shown by authenticator apps.
+	TOTPIssuer string `env:"totp_issuer" envDefault:"main"`
+
+	// PasswordHashAlgorithm is bcrypt or argon2id. Hashes made with another
+	// algorithm or cost are replaced on the next successful login.
+	// PasswordArgon2Memory is in KiB.
+	PasswordHashAlgorithm string `env:"password_hash_algorithm" envDefault:"argon2id"`
+	PasswordBcryptCost    int    `env:"password_bcrypt_cost" envDefault:"10"`
+	PasswordArgon2Time    int    `env:"password_argon2_time" envDefault:"3"`
+	PasswordArgon2Memory  int    `env:"password_argon2_memory" envDefault:"65536"`
+	PasswordArgon2Threads int    `env:"password_argon2_threads" envDefault:"2"`
 }
 
 var (
@@ -0,0 +1,68 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EarthRadiusKm is the mean radius of the earth used for great-circle
+// distances.
+const EarthRadiusKm = 6371.0
+
+// KmPerDegree is the length of a degree of latitude.
+const KmPerDegree = math.Pi * EarthRadiusKm / 180
+
+var (
+	ErrInvalidCoordinate = errors.New("invalid coordinate")
+	ErrOutOfRange        = errors.New("coordinate out of range")
+	ErrIncomplete        = errors.New("latitude and longitude must be set together")
+)
+
+// ValidLatitude reports whether lat is within -90..90.
+func ValidLatitude(lat float64) bool {
+	return lat >= -90 && lat <= 90
+}
+
+// ValidLongitude reports whether long is within -180..180.
+func ValidLongitude(long float64) bool {
+	return long >= -180 && long <= 180
+}
+
+// Distance is the great-circle distance in kilometers between two points, by
+// the haversine formula.
+func Distance(lat1, long1, lat2, long2 float64) float64 {
+	const rad = math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLong := (long2 - long1) * rad
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Pow(math.Sin(dLong/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Sqrt(math.Min(1, a)))
+}
+
+// ParseCoordinates parses a latitude and longitude written as text. Both
+// empty means no coordinates and gives nil, nil.
+func ParseCoordinates(lat, long string) (*float64, *float64, error) {
+	lat, long = strings.TrimSpace(lat), strings.TrimSpace(long)
+	if lat == "" && long == "" {
+		return nil, nil, nil
+	}
+	if lat == "" || long == "" {
+		return nil, nil, ErrIncomplete
+	}
+
+	latitude, err := strconv.ParseFloat(lat, 64)
+	if err != nil || math.IsNaN(latitude) {
+		return nil, nil, ErrInvalidCoordinate
+	}
+	longitude, err := strconv.ParseFloat(long, 64)
+	if err != nil || math.IsNaN(longitude) {
+		return nil, nil, ErrInvalidCoordinate
+	}
+	if !ValidLatitude(latitude) || !ValidLongitude(longitude) {
+		return nil, nil, ErrOutOfRange
+	}
+
+	return &latitude, &longitude, nil
+}
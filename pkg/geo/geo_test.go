@@ -0,0 +1,70 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		name string
+		lat  string
+		long string
+		err  error
+		want []float64
+	}{
+		{name: "empty"},
+		{name: "blank", lat: " ", long: ""},
+		{name: "valid", lat: "37.7749", long: "-122.4194", want: []float64{37.7749, -122.4194}},
+		{name: "surrounding spaces", lat: " 30.0444 ", long: "31.2357 ", want: []float64{30.0444, 31.2357}},
+		{name: "bounds", lat: "-90", long: "180", want: []float64{-90, 180}},
+		{name: "latitude only", lat: "30", err: ErrIncomplete},
+		{name: "longitude only", long: "31", err: ErrIncomplete},
+		{name: "not a number", lat: "north", long: "31", err: ErrInvalidCoordinate},
+		{name: "decimal comma", lat: "30,04", long: "31,23", err: ErrInvalidCoordinate},
+		{name: "nan", lat: "NaN", long: "31", err: ErrInvalidCoordinate},
+		{name: "latitude out of range", lat: "90.5", long: "31", err: ErrOutOfRange},
+		{name: "longitude out of range", lat: "30", long: "-180.1", err: ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lat, long, err := ParseCoordinates(tt.lat, tt.long)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Nil(t, lat)
+				assert.Nil(t, long)
+				return
+			}
+			assert.Equal(t, tt.want[0], *lat)
+			assert.Equal(t, tt.want[1], *long)
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		from []float64
+		to   []float64
+		km   float64
+	}{
+		{name: "same point", from: []float64{30.0444, 31.2357}, to: []float64{30.0444, 31.2357}, km: 0},
+		{name: "cairo to alexandria", from: []float64{30.0444, 31.2357}, to: []float64{31.2001, 29.9187}, km: 179},
+		{name: "london to paris", from: []float64{51.5074, -0.1278}, to: []float64{48.8566, 2.3522}, km: 344},
+		{name: "across the dateline", from: []float64{0, 179.5}, to: []float64{0, -179.5}, km: 111},
+		{name: "antipodes", from: []float64{0, 0}, to: []float64{0, 180}, km: math.Pi * EarthRadiusKm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km := Distance(tt.from[0], tt.from[1], tt.to[0], tt.to[1])
+			assert.InDelta(t, tt.km, km, 1)
+		})
+	}
+}
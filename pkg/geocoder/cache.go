@@ -0,0 +1,58 @@
+package geocoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"main/pkg/config"
+	"main/pkg/redis"
+)
+
+// cached keeps the answers of another Geocoder in Redis, places that were not
+// found included.
+type cached struct {
+	next  Geocoder
+	cache redis.IRedis
+}
+
+// cachedLocation is a cached answer, Location is nil for places not found.
+type cachedLocation struct {
+	Location *Location `json:"location"`
+}
+
+// NewCached caches the answers of next for config.GeocodeCachingTime.
+func NewCached(next Geocoder, cache redis.IRedis) Geocoder {
+	return &cached{next: next, cache: cache}
+}
+
+func (c *cached) Geocode(ctx context.Context, city, street string) (*Location, error) {
+	return c.lookup(fmt.Sprintf("geocode:%s", nameKey(city, street)), func() (*Location, error) {
+		return c.next.Geocode(ctx, city, street)
+	})
+}
+
+func (c *cached) Reverse(ctx context.Context, lat, long float64) (*Location, error) {
+	// Five decimals are about a meter apart.
+	return c.lookup(fmt.Sprintf("geocode:reverse:%.5f,%.5f", lat, long), func() (*Location, error) {
+		return c.next.Reverse(ctx, lat, long)
+	})
+}
+
+func (c *cached) lookup(key string, find func() (*Location, error)) (*Location, error) {
+	var answer cachedLocation
+	if err := c.cache.Get(key, &answer); err == nil {
+		if answer.Location == nil {
+			return nil, ErrNotFound
+		}
+		return answer.Location, nil
+	}
+
+	location, err := find()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	_ = c.cache.SetWithExpiration(key, cachedLocation{Location: location}, config.GeocodeCachingTime)
+	return location, err
+}
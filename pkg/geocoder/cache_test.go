@@ -0,0 +1,98 @@
+package geocoder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"main/pkg/config"
+	"main/pkg/redis/mocks"
+)
+
+// countingGeocoder counts the lookups that reach it.
+type countingGeocoder struct {
+	Geocoder
+	calls int
+	err   error
+}
+
+func (c *countingGeocoder) Geocode(ctx context.Context, city, street string) (*Location, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.Geocoder.Geocode(ctx, city, street)
+}
+
+func (c *countingGeocoder) Reverse(ctx context.Context, lat, long float64) (*Location, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.Geocoder.Reverse(ctx, lat, long)
+}
+
+// cacheHit makes Get answer with the given cached value.
+func cacheHit(value cachedLocation) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		data, _ := json.Marshal(value)
+		_ = json.Unmarshal(data, args.Get(1))
+	}
+}
+
+func TestCached_Miss(t *testing.T) {
+	next := &countingGeocoder{Geocoder: newTestGazetteer(t)}
+	cache := mocks.NewIRedis(t)
+	cache.On("Get", "geocode:cairo|tahrir square", mock.Anything).Return(errors.New("redis: nil"))
+	cache.On("SetWithExpiration", "geocode:cairo|tahrir square", cachedLocation{
+		Location: &Location{City: "Cairo", Street: "Tahrir Square", Lat: 30.0444, Long: 31.2357},
+	}, config.GeocodeCachingTime).Return(nil)
+
+	location, err := NewCached(next, cache).Geocode(context.Background(), "Cairo", " Tahrir Square")
+	require.NoError(t, err)
+	assert.Equal(t, 30.0444, location.Lat)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestCached_Hit(t *testing.T) {
+	next := &countingGeocoder{Geocoder: newTestGazetteer(t)}
+	cache := mocks.NewIRedis(t)
+	cache.On("Get", "geocode:reverse:30.04440,31.23570", mock.Anything).
+		Return(nil).
+		Run(cacheHit(cachedLocation{Location: &Location{City: "Cairo", Street: "Tahrir Square"}}))
+
+	location, err := NewCached(next, cache).Reverse(context.Background(), 30.0444, 31.2357)
+	require.NoError(t, err)
+	assert.Equal(t, "Tahrir Square", location.Street)
+	assert.Equal(t, 0, next.calls)
+}
+
+func TestCached_NotFound(t *testing.T) {
+	next := &countingGeocoder{Geocoder: newTestGazetteer(t)}
+	cache := mocks.NewIRedis(t)
+	cache.On("Get", "geocode:atlantis|", mock.Anything).Return(errors.New("redis: nil")).Once()
+	cache.On("SetWithExpiration", "geocode:atlantis|", cachedLocation{}, config.GeocodeCachingTime).Return(nil)
+
+	_, err := NewCached(next, cache).Geocode(context.Background(), "Atlantis", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	cache.On("Get", "geocode:atlantis|", mock.Anything).Return(nil).Run(cacheHit(cachedLocation{}))
+	_, err = NewCached(next, cache).Geocode(context.Background(), "Atlantis", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestCached_ErrorNotCached(t *testing.T) {
+	next := &countingGeocoder{err: errors.New("unavailable")}
+	cache := mocks.NewIRedis(t)
+	cache.On("Get", mock.Anything, mock.Anything).Return(errors.New("redis: nil"))
+
+	_, err := NewCached(next, cache).Geocode(context.Background(), "Cairo", "")
+	assert.EqualError(t, err, "unavailable")
+	cache.AssertNotCalled(t, "SetWithExpiration", mock.Anything, mock.Anything, mock.Anything)
+}
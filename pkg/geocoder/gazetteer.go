@@ -0,0 +1,123 @@
+package geocoder
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"main/pkg/geo"
+)
+
+// Gazetteer is an offline Geocoder backed by a list of known places, read
+// from CSV with a city,street,lat,long header. Rows with an empty street are
+// the centers of their city, used for streets that are not listed.
+type Gazetteer struct {
+	places        []*Location
+	byName        map[string]*Location
+	maxDistanceKm float64
+}
+
+// LoadGazetteer reads the gazetteer file at path. Reverse only reports places
+// within maxDistanceKm of the coordinates.
+func LoadGazetteer(path string, maxDistanceKm float64) (*Gazetteer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewGazetteer(f, maxDistanceKm)
+}
+
+// NewGazetteer reads a gazetteer from r, see LoadGazetteer.
+func NewGazetteer(r io.Reader, maxDistanceKm float64) (*Gazetteer, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("gazetteer header: %w", err)
+	}
+	if strings.Join(header, ",") != "city,street,lat,long" {
+		return nil, errors.New("gazetteer header must be city,street,lat,long")
+	}
+
+	g := &Gazetteer{
+		byName:        make(map[string]*Location),
+		maxDistanceKm: maxDistanceKm,
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer: %w", err)
+		}
+
+		lat, err := strconv.ParseFloat(record[2], 64)
+		if err != nil || !geo.ValidLatitude(lat) {
+			return nil, fmt.Errorf("gazetteer: invalid latitude %q", record[2])
+		}
+		long, err := strconv.ParseFloat(record[3], 64)
+		if err != nil || !geo.ValidLongitude(long) {
+			return nil, fmt.Errorf("gazetteer: invalid longitude %q", record[3])
+		}
+
+		place := &Location{
+			City:   strings.TrimSpace(record[0]),
+			Street: strings.TrimSpace(record[1]),
+			Lat:    lat,
+			Long:   long,
+		}
+		g.places = append(g.places, place)
+		g.byName[nameKey(place.City, place.Street)] = place
+	}
+
+	return g, nil
+}
+
+// Geocode finds the street of city, or the center of city when the street is
+// not listed.
+func (g *Gazetteer) Geocode(ctx context.Context, city, street string) (*Location, error) {
+	place, ok := g.byName[nameKey(city, street)]
+	if !ok {
+		place, ok = g.byName[nameKey(city, "")]
+	}
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	location := *place
+	return &location, nil
+}
+
+// Reverse finds the place nearest to the coordinates.
+func (g *Gazetteer) Reverse(ctx context.Context, lat, long float64) (*Location, error) {
+	var nearest *Location
+	nearestKm := g.maxDistanceKm
+	for _, place := range g.places {
+		if km := geo.Distance(lat, long, place.Lat, place.Long); km <= nearestKm {
+			nearest, nearestKm = place, km
+		}
+	}
+	if nearest == nil {
+		return nil, ErrNotFound
+	}
+
+	location := *nearest
+	return &location, nil
+}
+
+// nameKey ignores case and repeated spaces in place names.
+func nameKey(city, street string) string {
+	return strings.ToLower(strings.Join(strings.Fields(city), " ")) + "|" +
+		strings.ToLower(strings.Join(strings.Fields(street), " "))
+}
@@ -0,0 +1,132 @@
+package geocoder
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGazetteer = `# test places
+city,street,lat,long
+Cairo,,30.0478,31.2336
+Cairo,Tahrir Square,30.0444,31.2357
+Giza,Pyramids Road,29.9925,31.1730
+`
+
+func newTestGazetteer(t *testing.T) *Gazetteer {
+	g, err := NewGazetteer(strings.NewReader(testGazetteer), 1)
+	require.NoError(t, err)
+	return g
+}
+
+func TestGazetteer_Geocode(t *testing.T) {
+	g := newTestGazetteer(t)
+
+	tests := []struct {
+		name   string
+		city   string
+		street string
+		want   *Location
+		err    error
+	}{
+		{
+			name:   "street",
+			city:   "Cairo",
+			street: "Tahrir Square",
+			want:   &Location{City: "Cairo", Street: "Tahrir Square", Lat: 30.0444, Long: 31.2357},
+		},
+		{
+			name:   "case and spaces",
+			city:   " cairo",
+			street: "tahrir   SQUARE ",
+			want:   &Location{City: "Cairo", Street: "Tahrir Square", Lat: 30.0444, Long: 31.2357},
+		},
+		{
+			name:   "unknown street falls back to the city",
+			city:   "Cairo",
+			street: "Unknown Street",
+			want:   &Location{City: "Cairo", Lat: 30.0478, Long: 31.2336},
+		},
+		{
+			name:   "unknown street of a city without center",
+			city:   "Giza",
+			street: "Unknown Street",
+			err:    ErrNotFound,
+		},
+		{
+			name: "unknown city",
+			city: "Atlantis",
+			err:  ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, err := g.Geocode(context.Background(), tt.city, tt.street)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, location)
+		})
+	}
+}
+
+func TestGazetteer_Reverse(t *testing.T) {
+	g := newTestGazetteer(t)
+
+	location, err := g.Reverse(context.Background(), 30.0445, 31.2360)
+	require.NoError(t, err)
+	assert.Equal(t, "Tahrir Square", location.Street)
+
+	location, err = g.Reverse(context.Background(), 30.0480, 31.2330)
+	require.NoError(t, err)
+	assert.Equal(t, "Cairo", location.City)
+	assert.Empty(t, location.Street)
+
+	_, err = g.Reverse(context.Background(), 31.2001, 29.9187)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGazetteer_ReturnsCopies(t *testing.T) {
+	g := newTestGazetteer(t)
+
+	location, err := g.Geocode(context.Background(), "Cairo", "")
+	require.NoError(t, err)
+	location.City = "Changed"
+
+	location, err = g.Geocode(context.Background(), "Cairo", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Cairo", location.City)
+}
+
+func TestNewGazetteer_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "wrong header", data: "name,lat,long\nCairo,30,31\n"},
+		{name: "missing column", data: "city,street,lat,long\nCairo,,30\n"},
+		{name: "latitude out of range", data: "city,street,lat,long\nCairo,,95,31\n"},
+		{name: "longitude not a number", data: "city,street,lat,long\nCairo,,30,east\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGazetteer(strings.NewReader(tt.data), 1)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadGazetteer_ShippedFile(t *testing.T) {
+	g, err := LoadGazetteer("../../data/gazetteer.csv", 1)
+	require.NoError(t, err)
+
+	location, err := g.Geocode(context.Background(), "London", "Baker Street")
+	require.NoError(t, err)
+	assert.InDelta(t, 51.5237, location.Lat, 0.0001)
+}
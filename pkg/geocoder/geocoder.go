@@ -0,0 +1,46 @@
+package geocoder
+
+import (
+	"context"
+	"errors"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/config"
+	"main/pkg/redis"
+)
+
+var ErrNotFound = errors.New("location not found")
+
+// Location is a place found by a Geocoder. Street is empty for places that
+// are only known down to the city.
+type Location struct {
+	City   string  `json:"city"`
+	Street string  `json:"street"`
+	Lat    float64 `json:"lat"`
+	Long   float64 `json:"long"`
+}
+
+// Geocoder turns a city and street into coordinates and back. Both return
+// ErrNotFound for places they do not know.
+//
+//go:generate mockery --name=Geocoder
+type Geocoder interface {
+	Geocode(ctx context.Context, city, street string) (*Location, error)
+	Reverse(ctx context.Context, lat, long float64) (*Location, error)
+}
+
+// NewFromConfig returns the cached gazetteer geocoder of cfg, or nil when
+// geocoding is disabled or the gazetteer cannot be loaded.
+func NewFromConfig(cfg *config.Schema, cache redis.IRedis) Geocoder {
+	if cfg.GeocoderGazetteerFile == "" {
+		return nil
+	}
+
+	gazetteer, err := LoadGazetteer(cfg.GeocoderGazetteerFile, config.GeocodeReverseMaxDistanceKm)
+	if err != nil {
+		logger.Error("Failed to load the gazetteer, geocoding is disabled: ", err)
+		return nil
+	}
+	return NewCached(gazetteer, cache)
+}
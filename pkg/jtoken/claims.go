@@ -0,0 +1,125 @@
+package jtoken
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"main/pkg/config"
+)
+
+var (
+	ErrTokenExpired     = errors.New("token is expired")
+	ErrTokenNotValidYet = errors.New("token is not valid yet")
+	ErrTokenIssuedLater = errors.New("token used before issued")
+	ErrInvalidIssuer    = errors.New("invalid token issuer")
+	ErrInvalidAudience  = errors.New("invalid token audience")
+)
+
+// Claims is the body of every token issued by this package. Times are unix
+// seconds as in RFC 7519.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	IssuedAt  int64    `json:"iat"`
+	NotBefore int64    `json:"nbf"`
+	ID        string   `json:"jti"`
+	SessionID string   `json:"sid,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Role      string   `json:"role,omitempty"`
+	Type      string   `json:"type"`
+	// Actor is set on impersonation tokens and names the admin acting as
+	// Subject, as the act claim of RFC 8693. ReadOnly limits such a token to
+	// requests that change nothing.
+	Actor    *Actor `json:"act,omitempty"`
+	ReadOnly bool   `json:"read_only,omitempty"`
+}
+
+// Actor is the party acting on behalf of the subject of a token.
+type Actor struct {
+	Subject string `json:"sub"`
+}
+
+// SessionOwner returns the user whose session the token belongs to. An
+// impersonation token lives in the session of its actor, so that it ends when
+// the admin logs out.
+func (c *Claims) SessionOwner() string {
+	if c.Actor != nil {
+		return c.Actor.Subject
+	}
+	return c.Subject
+}
+
+// Valid is called by the jwt parser when a token is parsed.
+func (c *Claims) Valid() error {
+	cfg := config.GetConfig()
+	return c.verify(time.Now(), cfg.JWTIssuer, cfg.JWTAudience, cfg.JWTClockSkew)
+}
+
+// verify checks the time based claims allowing for skew, and the issuer and
+// audience when they are configured.
+func (c *Claims) verify(now time.Time, issuer string, audience []string, skew time.Duration) error {
+	if c.ExpiresAt == 0 || now.Add(-skew).Unix() >= c.ExpiresAt {
+		return ErrTokenExpired
+	}
+
+	if now.Add(skew).Unix() < c.NotBefore {
+		return ErrTokenNotValidYet
+	}
+
+	if now.Add(skew).Unix() < c.IssuedAt {
+		return ErrTokenIssuedLater
+	}
+
+	if issuer != "" && c.Issuer != issuer {
+		return ErrInvalidIssuer
+	}
+
+	if len(audience) > 0 && !c.Audience.containsAny(audience) {
+		return ErrInvalidAudience
+	}
+
+	return nil
+}
+
+// Audience is the aud claim, which RFC 7519 allows to be a single string or
+// an array of strings.
+type Audience []string
+
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+
+	return json.Marshal([]string(a))
+}
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	*a = list
+	return nil
+}
+
+func (a Audience) containsAny(values []string) bool {
+	for _, aud := range a {
+		for _, value := range values {
+			if aud == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,117 @@
+package jtoken
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaims_Verify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	valid := Claims{
+		Subject:   "user-id",
+		Issuer:    "issuer",
+		Audience:  Audience{"api"},
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: now.Add(time.Minute).Unix(),
+	}
+
+	tests := []struct {
+		name     string
+		claims   func(c Claims) Claims
+		issuer   string
+		audience []string
+		skew     time.Duration
+		err      error
+	}{
+		{
+			name:     "valid",
+			claims:   func(c Claims) Claims { return c },
+			issuer:   "issuer",
+			audience: []string{"other", "api"},
+		},
+		{
+			name:   "expired",
+			claims: func(c Claims) Claims { c.ExpiresAt = now.Add(-time.Second).Unix(); return c },
+			err:    ErrTokenExpired,
+		},
+		{
+			name:   "expired within skew",
+			claims: func(c Claims) Claims { c.ExpiresAt = now.Add(-time.Second).Unix(); return c },
+			skew:   time.Minute,
+		},
+		{
+			name:   "missing exp",
+			claims: func(c Claims) Claims { c.ExpiresAt = 0; return c },
+			skew:   time.Minute,
+			err:    ErrTokenExpired,
+		},
+		{
+			name:   "not valid yet",
+			claims: func(c Claims) Claims { c.NotBefore = now.Add(time.Minute).Unix(); return c },
+			err:    ErrTokenNotValidYet,
+		},
+		{
+			name:   "not valid yet within skew",
+			claims: func(c Claims) Claims { c.NotBefore = now.Add(time.Second).Unix(); return c },
+			skew:   time.Minute,
+		},
+		{
+			name:   "issued in the future",
+			claims: func(c Claims) Claims { c.IssuedAt = now.Add(time.Minute).Unix(); return c },
+			err:    ErrTokenIssuedLater,
+		},
+		{
+			name:   "wrong issuer",
+			claims: func(c Claims) Claims { return c },
+			issuer: "other",
+			err:    ErrInvalidIssuer,
+		},
+		{
+			name:     "wrong audience",
+			claims:   func(c Claims) Claims { return c },
+			audience: []string{"other"},
+			err:      ErrInvalidAudience,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := tt.claims(valid)
+			err := claims.verify(now, tt.issuer, tt.audience, tt.skew)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestAudience_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		audience Audience
+	}{
+		{
+			name:     "single value",
+			data:     `"api"`,
+			audience: Audience{"api"},
+		},
+		{
+			name:     "list",
+			data:     `["api","web"]`,
+			audience: Audience{"api", "web"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var audience Audience
+			assert.NoError(t, json.Unmarshal([]byte(tt.data), &audience))
+			assert.Equal(t, tt.audience, audience)
+
+			data, err := json.Marshal(audience)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.data, string(data))
+		})
+	}
+}
@@ -5,10 +5,10 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	"github.com/quangdangfit/gocommon/logger"
 
 	"main/pkg/config"
-	"main/pkg/utils"
 )
 
 const (
@@ -16,17 +16,19 @@ const (
 	RefreshTokenExpiredTime = 30 * 24 * 3600
 	AccessTokenType         = "x-access"  // 5 minutes
 	RefreshTokenType        = "x-refresh" // 30 days
+
+	// MFA tokens prove a correct password and are exchanged, together with a
+	// second factor, for an access/refresh token pair.
+	MFATokenExpiredTime = 5 * 60
+	MFATokenType        = "x-mfa"
+
+	// Impersonation tokens are access tokens that let an admin act as a user,
+	// kept short as they cannot be refreshed.
+	ImpersonationTokenExpiredTime = 15 * 60
 )
 
-func GenerateAccessToken(payload map[string]interface{}) string {
-	cfg := config.GetConfig()
-	payload["type"] = AccessTokenType
-	tokenContent := jwt.MapClaims{
-		"payload": payload,
-		"exp":     time.Now().Add(time.Second * AccessTokenExpiredTime).Unix(),
-	}
-	jwtToken := jwt.NewWithClaims(jwt.GetSigningMethod("HS256"), tokenContent)
-	token, err := jwtToken.SignedString([]byte(cfg.AuthSecret))
+func GenerateAccessToken(claims Claims) string {
+	token, err := signToken(&claims, AccessTokenType, AccessTokenExpiredTime)
 	if err != nil {
 		logger.Error("Failed to generate access token: ", err)
 		return ""
@@ -35,15 +37,8 @@ func GenerateAccessToken(payload map[string]interface{}) string {
 	return token
 }
 
-func GenerateRefreshToken(payload map[string]interface{}) string {
-	cfg := config.GetConfig()
-	payload["type"] = RefreshTokenType
-	tokenContent := jwt.MapClaims{
-		"payload": payload,
-		"exp":     time.Now().Add(time.Second * RefreshTokenExpiredTime).Unix(),
-	}
-	jwtToken := jwt.NewWithClaims(jwt.GetSigningMethod("HS256"), tokenContent)
-	token, err := jwtToken.SignedString([]byte(cfg.AuthSecret))
+func GenerateRefreshToken(claims Claims) string {
+	token, err := signToken(&claims, RefreshTokenType, RefreshTokenExpiredTime)
 	if err != nil {
 		logger.Error("Failed to generate refresh token: ", err)
 		return ""
@@ -52,24 +47,77 @@ func GenerateRefreshToken(payload map[string]interface{}) string {
 	return token
 }
 
-func ValidateToken(jwtToken string) (map[string]interface{}, error) {
-	cfg := config.GetConfig()
-	cleanJWT := strings.Replace(jwtToken, "Bearer ", "", -1)
-	tokenData := jwt.MapClaims{}
-	token, err := jwt.ParseWithClaims(cleanJWT, tokenData, func(token *jwt.Token) (interface{}, error) {
-		return []byte(cfg.AuthSecret), nil
-	})
+func GenerateMFAToken(claims Claims) string {
+	token, err := signToken(&claims, MFATokenType, MFATokenExpiredTime)
+	if err != nil {
+		logger.Error("Failed to generate mfa token: ", err)
+		return ""
+	}
+
+	return token
+}
 
+// GenerateImpersonationToken returns an access token for claims.Subject acting
+// on behalf of claims.Actor.
+func GenerateImpersonationToken(claims Claims) string {
+	token, err := signToken(&claims, AccessTokenType, ImpersonationTokenExpiredTime)
+	if err != nil {
+		logger.Error("Failed to generate impersonation token: ", err)
+		return ""
+	}
+
+	return token
+}
+
+// ValidateToken checks the signature and claims of a token, with or without
+// the "Bearer " prefix.
+func ValidateToken(jwtToken string) (*Claims, error) {
+	ks, err := currentKeys()
 	if err != nil {
 		return nil, err
 	}
 
+	cleanJWT := strings.Replace(jwtToken, "Bearer ", "", -1)
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(cleanJWT, claims, ks.keyFunc)
+	if err != nil {
+		// jwt wraps our own errors, hand them back as is
+		if vErr, ok := err.(*jwt.ValidationError); ok && vErr.Inner != nil {
+			return nil, vErr.Inner
+		}
+		return nil, err
+	}
+
 	if !token.Valid {
 		return nil, jwt.ErrInvalidKey
 	}
 
-	var data map[string]interface{}
-	utils.Copy(&data, tokenData["payload"])
+	return claims, nil
+}
+
+// signToken fills the registered claims that are not set by the caller and
+// signs the token.
+func signToken(claims *Claims, tokenType string, expiredTime int64) (string, error) {
+	ks, err := currentKeys()
+	if err != nil {
+		return "", err
+	}
+
+	cfg := config.GetConfig()
+	now := time.Now()
+	claims.Type = tokenType
+	claims.IssuedAt = now.Unix()
+	claims.NotBefore = now.Unix()
+	claims.ExpiresAt = now.Add(time.Second * time.Duration(expiredTime)).Unix()
+	if claims.Issuer == "" {
+		claims.Issuer = cfg.JWTIssuer
+	}
+	if len(claims.Audience) == 0 {
+		claims.Audience = cfg.JWTAudience
+	}
+	if claims.ID == "" {
+		claims.ID = uuid.New().String()
+	}
 
-	return data, nil
+	return ks.sign(claims)
 }
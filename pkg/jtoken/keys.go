@@ -0,0 +1,327 @@
+package jtoken
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt"
+
+	"main/pkg/config"
+)
+
+var (
+	ErrInvalidKeyFile   = errors.New("invalid PEM key file")
+	ErrUnsupportedKey   = errors.New("unsupported key type, expected RSA or Ed25519")
+	ErrUnknownKeyID     = errors.New("unknown key id")
+	ErrUnexpectedMethod = errors.New("unexpected signing method")
+)
+
+// KeySet holds the key used to sign new tokens and every key that is still
+// accepted when validating them. Asymmetric keys are looked up by the kid
+// header, which is the RFC 7638 thumbprint of the public key.
+type KeySet struct {
+	method     jwt.SigningMethod
+	signingKey interface{}
+	signingKID string
+	verifyKeys map[string]crypto.PublicKey
+	// order keeps the JWKS output stable, signing key first.
+	order []string
+}
+
+// JWK is the public part of a verification key as published in the JWKS.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var (
+	keysMu sync.RWMutex
+	keys   *KeySet
+)
+
+// NewKeySet builds the key set described by cfg. Without a signing key file
+// tokens are signed with HS256 and cfg.AuthSecret, and nothing is published.
+func NewKeySet(cfg *config.Schema) (*KeySet, error) {
+	if cfg.JWTSigningKeyFile == "" {
+		return &KeySet{
+			method:     jwt.SigningMethodHS256,
+			signingKey: []byte(cfg.AuthSecret),
+		}, nil
+	}
+
+	data, err := os.ReadFile(cfg.JWTSigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.JWTSigningKeyFile, err)
+	}
+
+	method, err := signingMethod(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{
+		method:     method,
+		signingKey: signer,
+		verifyKeys: make(map[string]crypto.PublicKey),
+	}
+	if ks.signingKID, err = ks.addVerifyKey(signer.Public()); err != nil {
+		return nil, err
+	}
+
+	for _, path := range cfg.JWTVerifyKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := parsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if _, err = ks.addVerifyKey(key); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return ks, nil
+}
+
+// LoadKeys reads the keys configured in cfg and makes them the keys used to
+// sign and validate tokens. It is meant to be called once at startup so that
+// a broken key file stops the service instead of failing every request.
+func LoadKeys(cfg *config.Schema) error {
+	ks, err := NewKeySet(cfg)
+	if err != nil {
+		return err
+	}
+
+	keysMu.Lock()
+	keys = ks
+	keysMu.Unlock()
+	return nil
+}
+
+// PublicKeys returns the JWKS of the keys accepted by ValidateToken.
+func PublicKeys() JWKS {
+	ks, err := currentKeys()
+	if err != nil {
+		return JWKS{Keys: []JWK{}}
+	}
+
+	return ks.JWKS()
+}
+
+func currentKeys() (*KeySet, error) {
+	keysMu.RLock()
+	ks := keys
+	keysMu.RUnlock()
+	if ks != nil {
+		return ks, nil
+	}
+
+	if err := LoadKeys(config.GetConfig()); err != nil {
+		return nil, err
+	}
+
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+	return keys, nil
+}
+
+// JWKS returns the public keys of the set. Shared secrets are never published.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(ks.order))}
+	for _, kid := range ks.order {
+		jwk, err := toJWK(ks.verifyKeys[kid])
+		if err != nil {
+			continue
+		}
+
+		jwk.Kid = kid
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.method, claims)
+	if ks.signingKID != "" {
+		token.Header["kid"] = ks.signingKID
+	}
+
+	return token.SignedString(ks.signingKey)
+}
+
+// keyFunc picks the verification key for a token. HS256 sets only accept
+// HS256, asymmetric sets only accept the algorithm matching the kid's key.
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	if ks.verifyKeys == nil {
+		if token.Method.Alg() != ks.method.Alg() {
+			return nil, ErrUnexpectedMethod
+		}
+		return ks.signingKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.verifyKeys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+
+	method, err := signingMethod(key)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != method.Alg() {
+		return nil, ErrUnexpectedMethod
+	}
+
+	return key, nil
+}
+
+func (ks *KeySet) addVerifyKey(key crypto.PublicKey) (string, error) {
+	kid, err := thumbprint(key)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := ks.verifyKeys[kid]; !ok {
+		ks.verifyKeys[kid] = key
+		ks.order = append(ks.order, kid)
+	}
+	return kid, nil
+}
+
+func signingMethod(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// parsePrivateKey reads a PKCS#8 or PKCS#1 PEM private key.
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKeyFile
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, ErrUnsupportedKey
+		}
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, ErrInvalidKeyFile
+}
+
+// parsePublicKey reads a PEM public key. A private key is accepted as well,
+// in which case its public half is used.
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKeyFile
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	signer, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Public(), nil
+}
+
+func toJWK(key crypto.PublicKey) (JWK, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Use: "sig",
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k),
+		}, nil
+	default:
+		return JWK{}, ErrUnsupportedKey
+	}
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint used as kid.
+func thumbprint(key crypto.PublicKey) (string, error) {
+	jwk, err := toJWK(key)
+	if err != nil {
+		return "", err
+	}
+
+	// The members must be in lexicographic order, which encoding/json gives
+	// us for maps.
+	members := map[string]string{"kty": jwk.Kty}
+	if jwk.Kty == "RSA" {
+		members["e"] = jwk.E
+		members["n"] = jwk.N
+	} else {
+		members["crv"] = jwk.Crv
+		members["x"] = jwk.X
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
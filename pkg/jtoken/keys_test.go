@@ -0,0 +1,148 @@
+package jtoken
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"main/pkg/config"
+)
+
+func writePrivateKey(t *testing.T, key crypto.Signer) string {
+	data, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "private.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: data}), 0o600))
+	return path
+}
+
+func writePublicKey(t *testing.T, key crypto.PublicKey) string {
+	data, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: data}), 0o600))
+	return path
+}
+
+func useKeys(t *testing.T, cfg *config.Schema) {
+	require.NoError(t, LoadKeys(cfg))
+	t.Cleanup(func() {
+		keysMu.Lock()
+		keys = nil
+		keysMu.Unlock()
+	})
+}
+
+func TestKeySet_SignAndValidate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		cfg  *config.Schema
+		alg  string
+		jwks int
+	}{
+		{
+			name: "HS256 with auth secret",
+			cfg:  &config.Schema{AuthSecret: "secret"},
+			alg:  "HS256",
+			jwks: 0,
+		},
+		{
+			name: "RS256 key file",
+			cfg:  &config.Schema{JWTSigningKeyFile: writePrivateKey(t, rsaKey)},
+			alg:  "RS256",
+			jwks: 1,
+		},
+		{
+			name: "EdDSA key file",
+			cfg:  &config.Schema{JWTSigningKeyFile: writePrivateKey(t, edKey)},
+			alg:  "EdDSA",
+			jwks: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useKeys(t, tt.cfg)
+
+			token := GenerateAccessToken(Claims{Subject: "user-id"})
+			parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.alg, parsed.Method.Alg())
+
+			claims, err := ValidateToken("Bearer " + token)
+			assert.NoError(t, err)
+			assert.Equal(t, "user-id", claims.Subject)
+
+			jwks := PublicKeys()
+			assert.Equal(t, tt.jwks, len(jwks.Keys))
+			if tt.jwks > 0 {
+				assert.Equal(t, parsed.Header["kid"], jwks.Keys[0].Kid)
+				assert.Equal(t, tt.alg, jwks.Keys[0].Alg)
+			}
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	_, oldKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	useKeys(t, &config.Schema{JWTSigningKeyFile: writePrivateKey(t, oldKey)})
+	oldToken := GenerateAccessToken(Claims{Subject: "user-id"})
+
+	useKeys(t, &config.Schema{
+		JWTSigningKeyFile: writePrivateKey(t, newKey),
+		JWTVerifyKeyFiles: []string{writePublicKey(t, oldKey.Public())},
+	})
+	newToken := GenerateAccessToken(Claims{Subject: "user-id"})
+
+	_, err = ValidateToken(oldToken)
+	assert.NoError(t, err)
+	_, err = ValidateToken(newToken)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(PublicKeys().Keys))
+
+	useKeys(t, &config.Schema{JWTSigningKeyFile: writePrivateKey(t, newKey)})
+	_, err = ValidateToken(oldToken)
+	assert.Error(t, err)
+	_, err = ValidateToken(newToken)
+	assert.NoError(t, err)
+}
+
+func TestKeySet_RejectsHS256WithAsymmetricKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	useKeys(t, &config.Schema{AuthSecret: "secret"})
+	token := GenerateAccessToken(Claims{Subject: "user-id"})
+
+	useKeys(t, &config.Schema{AuthSecret: "secret", JWTSigningKeyFile: writePrivateKey(t, rsaKey)})
+	_, err = ValidateToken(token)
+	assert.Error(t, err)
+}
+
+func TestNewKeySet_InvalidKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o600))
+
+	_, err := NewKeySet(&config.Schema{JWTSigningKeyFile: path})
+	assert.ErrorIs(t, err, ErrInvalidKeyFile)
+}
@@ -0,0 +1,150 @@
+package jtoken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"main/pkg/redis"
+)
+
+var (
+	ErrSessionRevoked = errors.New("session has been revoked")
+	ErrTokenReused    = errors.New("refresh token has already been used")
+)
+
+// Session is the refresh token family created by a single login. Only the
+// refresh token whose jti equals TokenID may be exchanged, and every exchange
+// rotates TokenID and updates LastUsedAt.
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	TokenID    string    `json:"token_id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+//go:generate mockery --name=IStore
+type IStore interface {
+	Save(session *Session) error
+	Rotate(session *Session, tokenID string) error
+	Get(userID, sessionID string) (*Session, error)
+	List(userID string) ([]*Session, error)
+	Revoke(userID, sessionID string) error
+	RevokeAll(userID string) error
+}
+
+// Store keeps sessions in redis, keyed by user so that all sessions of a user
+// can be revoked at once.
+type Store struct {
+	cache redis.IRedis
+}
+
+func NewStore(cache redis.IRedis) *Store {
+	return &Store{cache: cache}
+}
+
+func sessionKey(userID, sessionID string) string {
+	return fmt.Sprintf("session:%s:%s", userID, sessionID)
+}
+
+func (s *Store) Save(session *Session) error {
+	return s.cache.SetWithExpiration(
+		sessionKey(session.UserID, session.ID),
+		session,
+		time.Second*RefreshTokenExpiredTime,
+	)
+}
+
+// rotateScript replaces the session at KEYS[1] with ARGV[2] for ARGV[3]
+// milliseconds, if it still expects the refresh token ARGV[1]. It returns 0
+// when the session is gone and -1 when the token was already exchanged.
+const rotateScript = `
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return 0
+end
+if cjson.decode(current)['token_id'] ~= ARGV[1] then
+	return -1
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`
+
+// Rotate saves session in place of the stored one, provided the stored
+// session still expects the refresh token tokenID. The check and the write
+// are one atomic step, so of two exchanges of the same token only one
+// succeeds and the other gets ErrTokenReused.
+func (s *Store) Rotate(session *Session, tokenID string) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	expiration := time.Second * RefreshTokenExpiredTime
+	result, err := s.cache.Eval(
+		rotateScript,
+		[]string{sessionKey(session.UserID, session.ID)},
+		tokenID, data, expiration.Milliseconds(),
+	)
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case int64(1):
+		return nil
+	case int64(-1):
+		return ErrTokenReused
+	default:
+		return ErrSessionRevoked
+	}
+}
+
+func (s *Store) Get(userID, sessionID string) (*Session, error) {
+	if userID == "" || sessionID == "" {
+		return nil, ErrSessionRevoked
+	}
+
+	var session Session
+	if err := s.cache.Get(sessionKey(userID, sessionID), &session); err != nil {
+		return nil, ErrSessionRevoked
+	}
+
+	return &session, nil
+}
+
+// List returns the sessions of a user, most recently used first.
+func (s *Store) List(userID string) ([]*Session, error) {
+	keys, err := s.cache.Keys(sessionKey(userID, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*Session, 0, len(keys))
+	for _, key := range keys {
+		var session Session
+		// The session may have expired or been revoked since Keys.
+		if err = s.cache.Get(key, &session); err != nil {
+			continue
+		}
+		sessions = append(sessions, &session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+func (s *Store) Revoke(userID, sessionID string) error {
+	return s.cache.Remove(sessionKey(userID, sessionID))
+}
+
+func (s *Store) RevokeAll(userID string) error {
+	return s.cache.RemovePattern(sessionKey(userID, "*"))
+}
@@ -0,0 +1,75 @@
+package jtoken
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"main/pkg/redis/mocks"
+)
+
+func TestStore_List(t *testing.T) {
+	now := time.Now()
+	stored := map[string]Session{
+		"session:user-id:old": {ID: "old", UserID: "user-id", LastUsedAt: now.Add(-time.Hour)},
+		"session:user-id:new": {ID: "new", UserID: "user-id", LastUsedAt: now},
+	}
+
+	cache := mocks.NewIRedis(t)
+	cache.On("Keys", "session:user-id:*").Return(
+		[]string{"session:user-id:old", "session:user-id:gone", "session:user-id:new"}, nil,
+	)
+	cache.On("Get", mock.Anything, mock.Anything).Return(func(key string, value interface{}) error {
+		session, ok := stored[key]
+		if !ok {
+			return errors.New("redis: nil")
+		}
+		*value.(*Session) = session
+		return nil
+	})
+
+	sessions, err := NewStore(cache).List("user-id")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(sessions))
+	assert.Equal(t, "new", sessions[0].ID)
+	assert.Equal(t, "old", sessions[1].ID)
+}
+
+func TestStore_ListError(t *testing.T) {
+	cache := mocks.NewIRedis(t)
+	cache.On("Keys", "session:user-id:*").Return(nil, errors.New("error"))
+
+	sessions, err := NewStore(cache).List("user-id")
+	assert.Error(t, err)
+	assert.Nil(t, sessions)
+}
+
+func TestStore_Rotate(t *testing.T) {
+	tests := []struct {
+		name   string
+		result interface{}
+		err    error
+	}{
+		{name: "swapped", result: int64(1)},
+		{name: "token already exchanged", result: int64(-1), err: ErrTokenReused},
+		{name: "session gone", result: int64(0), err: ErrSessionRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewIRedis(t)
+			cache.On("Eval", rotateScript, []string{"session:user-id:session-id"}, "old-token", mock.Anything, mock.Anything).
+				Return(tt.result, nil)
+
+			session := &Session{ID: "session-id", UserID: "user-id", TokenID: "new-token"}
+			err := NewStore(cache).Rotate(session, "old-token")
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/config"
+)
+
+const (
+	SMTPDriver = "smtp"
+	LogDriver  = "log"
+)
+
+// IMailer interface
+//
+//go:generate mockery --name=IMailer
+type IMailer interface {
+	Send(to, subject, body string) error
+}
+
+// New returns the mailer selected by cfg.MailDriver. Anything other than smtp
+// falls back to the log mailer so that local setups work without a mail server.
+func New(cfg *config.Schema) IMailer {
+	if cfg.MailDriver == SMTPDriver {
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+
+	return NewLogMailer(cfg.MailLogFile)
+}
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) IMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpMailer{
+		addr: fmt.Sprintf("%s:%d", host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *smtpMailer) Send(to, subject, body string) error {
+	msg := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg))
+}
+
+// logMailer writes messages to the application log and, when a path is set,
+// appends them to that file instead of delivering them.
+type logMailer struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewLogMailer(path string) IMailer {
+	return &logMailer{path: path}
+}
+
+func (m *logMailer) Send(to, subject, body string) error {
+	logger.Infof("Mail to: %s, subject: %s, body: %s", to, subject, body)
+	if m.path == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), to, subject, body)
+	return err
+}
@@ -5,35 +5,94 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"main/pkg/apikey"
 	"main/pkg/jtoken"
+	"main/pkg/redis"
 )
 
-func JWTAuth() gin.HandlerFunc {
-	return JWT(jtoken.AccessTokenType)
+// APIKeyHeader carries the API key of machine clients, in place of the
+// Authorization header.
+const APIKeyHeader = "X-API-Key"
+
+func JWTAuth(cache redis.IRedis) gin.HandlerFunc {
+	return JWT(jtoken.AccessTokenType, cache, nil)
+}
+
+// JWTAuthOrAPIKey accepts an access token or an API key. Routes using it
+// should mount RequireScope to limit what keys may do.
+func JWTAuthOrAPIKey(cache redis.IRedis, keys apikey.IStore) gin.HandlerFunc {
+	return JWT(jtoken.AccessTokenType, cache, keys)
 }
 
-func JWTRefresh() gin.HandlerFunc {
-	return JWT(jtoken.RefreshTokenType)
+func JWTRefresh(cache redis.IRedis) gin.HandlerFunc {
+	return JWT(jtoken.RefreshTokenType, cache, nil)
 }
 
-func JWT(tokenType string) gin.HandlerFunc {
-	print("Authorization:- ", tokenType)
+// JWT authenticates requests with a token of tokenType and, when keys is not
+// nil, with an API key sent in APIKeyHeader.
+func JWT(tokenType string, cache redis.IRedis, keys apikey.IStore) gin.HandlerFunc {
+	store := jtoken.NewStore(cache)
 	return func(c *gin.Context) {
+		if key := c.GetHeader(APIKeyHeader); key != "" && keys != nil {
+			apiKey, err := keys.Authenticate(c, key)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, nil)
+				c.Abort()
+				return
+			}
+
+			c.Set(principalKey, NewAPIKeyPrincipal(apiKey))
+			c.Next()
+			return
+		}
+
+		// Browsers in cookie auth mode send the token as a cookie, which
+		// they attach to cross-site requests as well, so changes need the
+		// CSRF token too.
 		token := c.GetHeader("Authorization")
+		fromCookie := false
+		if token == "" {
+			token = cookieToken(c, tokenType)
+			fromCookie = true
+		}
 		if token == "" {
 			c.JSON(http.StatusUnauthorized, nil)
 			c.Abort()
 			return
 		}
+		if fromCookie && !validCSRF(c) {
+			c.JSON(http.StatusForbidden, nil)
+			c.Abort()
+			return
+		}
 
-		payload, err := jtoken.ValidateToken(token)
-		if err != nil || payload == nil || payload["type"] != tokenType {
+		claims, err := jtoken.ValidateToken(token)
+		if err != nil || claims.Type != tokenType {
 			c.JSON(http.StatusUnauthorized, nil)
 			c.Abort()
 			return
 		}
-		c.Set("userId", payload["id"])
-		c.Set("role", payload["role"])
+
+		// Tokens stop working as soon as their session is revoked (logout,
+		// refresh token reuse), even before they expire.
+		if _, err := store.Get(claims.SessionOwner(), claims.SessionID); err != nil {
+			c.JSON(http.StatusUnauthorized, nil)
+			c.Abort()
+			return
+		}
+
+		principal := NewPrincipal(claims)
+		c.Set(principalKey, principal)
+		if principal.ReadOnly && !isSafeMethod(c.Request.Method) {
+			c.JSON(http.StatusForbidden, nil)
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
+
+// isSafeMethod reports whether an HTTP method only reads.
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
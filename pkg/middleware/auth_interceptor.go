@@ -2,24 +2,58 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
-	"log"
+	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"main/pkg/apikey"
 	"main/pkg/jtoken"
+	"main/pkg/redis"
 )
 
+// APIKeyMetadata carries the API key of machine clients, in place of the
+// authorization metadata.
+const APIKeyMetadata = "x-api-key"
+
+var errUnauthenticated = status.Error(codes.Unauthenticated, "unauthorized")
+
+// refreshTokenCarrier is implemented by requests that send the refresh token
+// in their body, like RefreshTokenReq.
+type refreshTokenCarrier interface {
+	GetRefreshToken() string
+}
+
 type AuthInterceptor struct {
 	ignoredMethods []string
+	refreshMethods []string
+	methodRoles    map[string][]string
+	methodScopes   map[string]string
+	store          jtoken.IStore
+	keys           apikey.IStore
 }
 
-func NewAuthInterceptor(ignoredMethods []string) *AuthInterceptor {
+// NewAuthInterceptor authenticates calls with an access token or, when keys
+// is not nil, an API key. refreshMethods take a refresh token instead, read
+// from the request when it carries one. API keys are only accepted on the
+// methods listed in methodScopes and need the scope listed there.
+func NewAuthInterceptor(
+	ignoredMethods []string,
+	refreshMethods []string,
+	methodRoles map[string][]string,
+	methodScopes map[string]string,
+	cache redis.IRedis,
+	keys apikey.IStore,
+) *AuthInterceptor {
 	return &AuthInterceptor{
 		ignoredMethods: ignoredMethods,
+		refreshMethods: refreshMethods,
+		methodRoles:    methodRoles,
+		methodScopes:   methodScopes,
+		store:          jtoken.NewStore(cache),
+		keys:           keys,
 	}
 }
 
@@ -30,44 +64,102 @@ func (ai *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		for _, m := range ai.ignoredMethods {
-			if info.FullMethod == m {
-				return handler(ctx, req)
+		if contains(ai.ignoredMethods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		var principal *Principal
+		var err error
+		if contains(ai.refreshMethods, info.FullMethod) {
+			principal, err = ai.authorizeRefresh(ctx, req)
+		} else {
+			principal, err = ai.authorize(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if principal.APIKeyID != "" {
+			scope, ok := ai.methodScopes[info.FullMethod]
+			if !ok || !principal.HasScope(scope) {
+				return nil, status.New(codes.PermissionDenied, "permission denied").Err()
 			}
 		}
 
-		ctx, userID, err := ai.authorize(ctx)
+		if roles, ok := ai.methodRoles[info.FullMethod]; ok {
+			if !hasRole(principal.Role, roles) {
+				return nil, status.New(codes.PermissionDenied, "permission denied").Err()
+			}
+		}
+
+		return handler(WithPrincipal(ctx, principal), req)
+	}
+}
+
+// authorize authenticates a call with an API key or an access token.
+func (ai *AuthInterceptor) authorize(ctx context.Context) (*Principal, error) {
+	m, _ := metadata.FromIncomingContext(ctx)
+	if key := m.Get(APIKeyMetadata); len(key) != 0 && ai.keys != nil {
+		apiKey, err := ai.keys.Authenticate(ctx, key[0])
 		if err != nil {
-			return nil, status.New(codes.Internal, err.Error()).Err()
+			return nil, errUnauthenticated
 		}
+		return NewAPIKeyPrincipal(apiKey), nil
+	}
 
-		// attach "userId" to context
-		ctx = context.WithValue(ctx, "userId", userID)
+	return ai.validate(ctx, bearerToken(m), jtoken.AccessTokenType)
+}
 
-		return handler(ctx, req)
+// authorizeRefresh authenticates a call with the refresh token of the
+// request, or of the authorization metadata when the request has none.
+func (ai *AuthInterceptor) authorizeRefresh(ctx context.Context, req interface{}) (*Principal, error) {
+	if carrier, ok := req.(refreshTokenCarrier); ok && carrier.GetRefreshToken() != "" {
+		return ai.validate(ctx, carrier.GetRefreshToken(), jtoken.RefreshTokenType)
 	}
+
+	m, _ := metadata.FromIncomingContext(ctx)
+	return ai.validate(ctx, bearerToken(m), jtoken.RefreshTokenType)
 }
 
-func (ai *AuthInterceptor) authorize(ctx context.Context) (context.Context, string, error) {
-	m, ok := metadata.FromIncomingContext(ctx)
-	if !ok || len(m["token"]) == 0 {
-		return ctx, "", status.New(codes.Unauthenticated, "missing token").Err()
+// validate checks token like JWT does: it must be valid, of tokenType and its
+// session must not be revoked.
+func (ai *AuthInterceptor) validate(ctx context.Context, token, tokenType string) (*Principal, error) {
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
 	}
 
-	payload, err := jtoken.ValidateToken(m["token"][0])
-	if err != nil {
-		return ctx, "", status.New(codes.Unauthenticated, "unauthorized").Err()
+	claims, err := jtoken.ValidateToken(token)
+	if err != nil || claims.Type != tokenType {
+		return nil, errUnauthenticated
 	}
 
-	var meta map[string]interface{}
-	b, err := json.Marshal(payload)
-	if err != nil {
-		return ctx, "", status.New(codes.Unauthenticated, "unauthorized").Err()
-	} else {
-		if err := json.Unmarshal(b, &meta); err != nil {
-			log.Println("Error while unmarshalling auth data", err)
-		}
+	if _, err := ai.store.Get(claims.SessionOwner(), claims.SessionID); err != nil {
+		return nil, errUnauthenticated
+	}
+
+	return NewPrincipal(claims), nil
+}
+
+// bearerToken returns the token of the "authorization: Bearer <token>"
+// metadata.
+func bearerToken(m metadata.MD) string {
+	values := m.Get("authorization")
+	if len(values) == 0 {
+		return ""
 	}
 
-	return ctx, payload["id"].(string), nil
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
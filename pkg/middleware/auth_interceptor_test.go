@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"main/pkg/jtoken"
+	"main/pkg/redis/mocks"
+)
+
+type refreshReq struct {
+	token string
+}
+
+func (r *refreshReq) GetRefreshToken() string {
+	return r.token
+}
+
+func TestAuthInterceptor_TokenTypes(t *testing.T) {
+	cache := mocks.NewIRedis(t)
+	cache.On("Get", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	interceptor := NewAuthInterceptor(
+		[]string{"/test.Service/Open"},
+		[]string{"/test.Service/Refresh"},
+		nil, nil, cache, nil,
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		principal, _ := GetPrincipal(ctx)
+		return principal, nil
+	}
+
+	claims := jtoken.Claims{Subject: "user-id", SessionID: "session-id", ID: "token-id"}
+	access := jtoken.GenerateAccessToken(claims)
+	refresh := jtoken.GenerateRefreshToken(claims)
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		req           interface{}
+		code          codes.Code
+		tokenType     string
+	}{
+		{
+			name:          "access token",
+			method:        "/test.Service/Call",
+			authorization: "Bearer " + access,
+			code:          codes.OK,
+			tokenType:     jtoken.AccessTokenType,
+		},
+		{
+			name:          "refresh token on access method",
+			method:        "/test.Service/Call",
+			authorization: "Bearer " + refresh,
+			code:          codes.Unauthenticated,
+		},
+		{
+			name:          "token without bearer scheme",
+			method:        "/test.Service/Call",
+			authorization: access,
+			code:          codes.Unauthenticated,
+		},
+		{
+			name:   "missing token",
+			method: "/test.Service/Call",
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:          "invalid token",
+			method:        "/test.Service/Call",
+			authorization: "Bearer invalid",
+			code:          codes.Unauthenticated,
+		},
+		{
+			name:      "refresh token in request",
+			method:    "/test.Service/Refresh",
+			req:       &refreshReq{token: refresh},
+			code:      codes.OK,
+			tokenType: jtoken.RefreshTokenType,
+		},
+		{
+			name:          "refresh token in metadata",
+			method:        "/test.Service/Refresh",
+			authorization: "Bearer " + refresh,
+			req:           &refreshReq{},
+			code:          codes.OK,
+			tokenType:     jtoken.RefreshTokenType,
+		},
+		{
+			name:   "access token on refresh method",
+			method: "/test.Service/Refresh",
+			req:    &refreshReq{token: access},
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "ignored method",
+			method: "/test.Service/Open",
+			code:   codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+
+			res, err := interceptor.Unary()(ctx, tt.req, info, handler)
+			assert.Equal(t, tt.code, status.Code(err))
+			if tt.tokenType != "" {
+				assert.Equal(t, tt.tokenType, res.(*Principal).TokenType)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"main/pkg/apikey"
+	"main/pkg/redis/mocks"
+)
+
+// fakeKeys accepts the keys it holds, by raw value.
+type fakeKeys map[string]*apikey.Key
+
+func (f fakeKeys) Create(ctx context.Context, key *apikey.Key) (string, error) { return "", nil }
+func (f fakeKeys) List(ctx context.Context) ([]*apikey.Key, error)             { return nil, nil }
+func (f fakeKeys) Revoke(ctx context.Context, id string) error                 { return nil }
+func (f fakeKeys) RevokeAll(ctx context.Context, userID string) error          { return nil }
+func (f fakeKeys) UpdateRole(ctx context.Context, userID, role string) error   { return nil }
+
+func (f fakeKeys) Authenticate(ctx context.Context, raw string) (*apikey.Key, error) {
+	key, ok := f[raw]
+	if !ok {
+		return nil, apikey.ErrInvalidKey
+	}
+	return key, nil
+}
+
+var testKeys = fakeKeys{
+	"ak_reader": {
+		ID:     "key-id",
+		UserID: "admin-id",
+		Role:   "admin",
+		Scopes: []string{apikey.ScopeUsersRead},
+	},
+}
+
+func TestJWTAuthOrAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name  string
+		key   string
+		scope string
+		code  int
+	}{
+		{
+			name:  "key with scope",
+			key:   "ak_reader",
+			scope: apikey.ScopeUsersRead,
+			code:  http.StatusOK,
+		},
+		{
+			name:  "key without scope",
+			key:   "ak_reader",
+			scope: apikey.ScopeUsersWrite,
+			code:  http.StatusForbidden,
+		},
+		{
+			name:  "unknown key",
+			key:   "ak_unknown",
+			scope: apikey.ScopeUsersRead,
+			code:  http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", JWTAuthOrAPIKey(mocks.NewIRedis(t), testKeys), RequireScope(tt.scope), func(c *gin.Context) {
+				principal, _ := GetPrincipal(c)
+				assert.Equal(t, "admin-id", principal.UserID)
+				assert.Equal(t, "key-id", principal.APIKeyID)
+				c.Status(http.StatusOK)
+			})
+
+			request, _ := http.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set(APIKeyHeader, tt.key)
+			writer := httptest.NewRecorder()
+			router.ServeHTTP(writer, request)
+			assert.Equal(t, tt.code, writer.Code)
+		})
+	}
+}
+
+func TestJWTAuth_RejectsAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/", JWTAuth(mocks.NewIRedis(t)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(APIKeyHeader, "ak_reader")
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, request)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestAuthInterceptor_APIKeyScopes(t *testing.T) {
+	interceptor := NewAuthInterceptor(nil, nil, map[string][]string{
+		"/test.Service/Admin": {"admin"},
+	}, map[string]string{
+		"/test.Service/Admin": apikey.ScopeUsersRead,
+		"/test.Service/Write": apikey.ScopeUsersWrite,
+	}, mocks.NewIRedis(t), testKeys)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name   string
+		key    string
+		method string
+		code   codes.Code
+	}{
+		{
+			name:   "key with scope",
+			key:    "ak_reader",
+			method: "/test.Service/Admin",
+			code:   codes.OK,
+		},
+		{
+			name:   "key without scope",
+			key:    "ak_reader",
+			method: "/test.Service/Write",
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "method without scope",
+			key:    "ak_reader",
+			method: "/test.Service/Other",
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "unknown key",
+			key:    "ak_unknown",
+			method: "/test.Service/Admin",
+			code:   codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(APIKeyMetadata, tt.key))
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+
+			_, err := interceptor.Unary()(ctx, nil, info, handler)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"main/pkg/config"
+	"main/pkg/jtoken"
+	"main/pkg/utils"
+)
+
+// Cookies of the cookie auth mode. The CSRF cookie is readable by scripts so
+// that browsers can echo it in CSRFHeader, the double-submit pattern.
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CSRFCookie         = "csrf_token"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+// SetTokenCookies hands the tokens of a login or refresh to the browser when
+// cookie auth is enabled. It reports which tokens were set as cookies, the
+// caller then leaves them out of the response body.
+func SetTokenCookies(c *gin.Context, accessToken, refreshToken string) (accessSet, refreshSet bool, err error) {
+	cfg := config.GetConfig()
+	if !cfg.AuthCookies {
+		return false, false, nil
+	}
+
+	csrf, err := utils.GenerateToken(config.CSRFTokenSize)
+	if err != nil {
+		return false, false, err
+	}
+
+	refreshAge := time.Second * jtoken.RefreshTokenExpiredTime
+	setCookie(c, cfg, RefreshTokenCookie, refreshToken, refreshAge, true)
+	setCookie(c, cfg, CSRFCookie, csrf, refreshAge, false)
+	if cfg.AuthCookieAccessToken {
+		setCookie(c, cfg, AccessTokenCookie, accessToken, time.Second*jtoken.AccessTokenExpiredTime, true)
+	}
+
+	return cfg.AuthCookieAccessToken, true, nil
+}
+
+// ClearTokenCookies removes the cookies of SetTokenCookies, like on logout.
+func ClearTokenCookies(c *gin.Context) {
+	cfg := config.GetConfig()
+	if !cfg.AuthCookies {
+		return
+	}
+
+	for _, name := range []string{AccessTokenCookie, RefreshTokenCookie, CSRFCookie} {
+		setCookie(c, cfg, name, "", -time.Second, name != CSRFCookie)
+	}
+}
+
+func setCookie(c *gin.Context, cfg *config.Schema, name, value string, maxAge time.Duration, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   cfg.AuthCookieDomain,
+		MaxAge:   int(maxAge.Seconds()),
+		Secure:   cfg.AuthCookieSecure,
+		HttpOnly: httpOnly,
+		SameSite: sameSite(cfg.AuthCookieSameSite),
+	})
+}
+
+func sameSite(mode string) http.SameSite {
+	switch strings.ToLower(mode) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// cookieToken returns the token of tokenType sent as a cookie, when cookie
+// auth is enabled.
+func cookieToken(c *gin.Context, tokenType string) string {
+	if !config.GetConfig().AuthCookies {
+		return ""
+	}
+
+	name := AccessTokenCookie
+	if tokenType == jtoken.RefreshTokenType {
+		name = RefreshTokenCookie
+	}
+	token, err := c.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// validCSRF reports whether the request echoes the CSRF cookie in CSRFHeader.
+// Requests that only read need no CSRF token.
+func validCSRF(c *gin.Context) bool {
+	if isSafeMethod(c.Request.Method) {
+		return true
+	}
+
+	cookie, err := c.Cookie(CSRFCookie)
+	header := c.GetHeader(CSRFHeader)
+	if err != nil || cookie == "" || header == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
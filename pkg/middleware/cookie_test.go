@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"main/pkg/config"
+	"main/pkg/jtoken"
+	"main/pkg/redis/mocks"
+)
+
+// enableCookies turns cookie auth on for the duration of a test.
+func enableCookies(t *testing.T, accessToken bool) {
+	cfg := config.GetConfig()
+	previous := *cfg
+	cfg.AuthCookies = true
+	cfg.AuthCookieAccessToken = accessToken
+	cfg.AuthCookieSecure = true
+	cfg.AuthCookieSameSite = "strict"
+	t.Cleanup(func() { *cfg = previous })
+}
+
+func cookiesOf(writer *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range writer.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+func TestSetTokenCookies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		accessToken bool
+		cookies     []string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:    "refresh token",
+			enabled: true,
+			cookies: []string{RefreshTokenCookie, CSRFCookie},
+		},
+		{
+			name:        "both tokens",
+			enabled:     true,
+			accessToken: true,
+			cookies:     []string{AccessTokenCookie, RefreshTokenCookie, CSRFCookie},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.enabled {
+				enableCookies(t, tt.accessToken)
+			}
+
+			writer := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(writer)
+			accessSet, refreshSet, err := SetTokenCookies(c, "access", "refresh")
+			require.NoError(t, err)
+			assert.Equal(t, tt.accessToken, accessSet)
+			assert.Equal(t, tt.enabled, refreshSet)
+
+			cookies := cookiesOf(writer)
+			assert.Len(t, cookies, len(tt.cookies))
+			for _, name := range tt.cookies {
+				cookie := cookies[name]
+				require.NotNil(t, cookie, name)
+				assert.True(t, cookie.Secure)
+				assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+				assert.Equal(t, name != CSRFCookie, cookie.HttpOnly, name)
+				assert.NotEmpty(t, cookie.Value)
+			}
+		})
+	}
+}
+
+func TestJWTAuth_Cookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	enableCookies(t, true)
+
+	access := jtoken.GenerateAccessToken(jtoken.Claims{Subject: "user-id", SessionID: "session-id"})
+	refresh := jtoken.GenerateRefreshToken(jtoken.Claims{Subject: "user-id", SessionID: "session-id"})
+
+	tests := []struct {
+		name    string
+		method  string
+		refresh bool
+		cookie  string
+		csrf    string
+		header  string
+		code    int
+	}{
+		{
+			name:   "read with cookie",
+			method: http.MethodGet,
+			cookie: access,
+			code:   http.StatusOK,
+		},
+		{
+			name:   "write with cookie and csrf token",
+			method: http.MethodPost,
+			cookie: access,
+			csrf:   "csrf",
+			code:   http.StatusOK,
+		},
+		{
+			name:   "write with cookie without csrf token",
+			method: http.MethodPost,
+			cookie: access,
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "write with cookie and wrong csrf token",
+			method: http.MethodPost,
+			cookie: access,
+			csrf:   "other",
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "write with header",
+			method: http.MethodPost,
+			header: "Bearer " + access,
+			code:   http.StatusOK,
+		},
+		{
+			name:    "refresh with cookie",
+			method:  http.MethodPost,
+			refresh: true,
+			cookie:  refresh,
+			csrf:    "csrf",
+			code:    http.StatusOK,
+		},
+		{
+			name:   "refresh token in access cookie",
+			method: http.MethodGet,
+			cookie: refresh,
+			code:   http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewIRedis(t)
+			cache.On("Get", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			auth, cookie := JWTAuth(cache), AccessTokenCookie
+			if tt.refresh {
+				auth, cookie = JWTRefresh(cache), RefreshTokenCookie
+			}
+			router := gin.New()
+			router.Handle(tt.method, "/", auth, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			request, _ := http.NewRequest(tt.method, "/", nil)
+			if tt.cookie != "" {
+				request.AddCookie(&http.Cookie{Name: cookie, Value: tt.cookie})
+				request.AddCookie(&http.Cookie{Name: CSRFCookie, Value: "csrf"})
+			}
+			if tt.csrf != "" {
+				request.Header.Set(CSRFHeader, tt.csrf)
+			}
+			if tt.header != "" {
+				request.Header.Set("Authorization", tt.header)
+			}
+			writer := httptest.NewRecorder()
+			router.ServeHTTP(writer, request)
+			assert.Equal(t, tt.code, writer.Code)
+		})
+	}
+}
+
+func TestJWTAuth_CookieDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/", JWTAuth(mocks.NewIRedis(t)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	access := jtoken.GenerateAccessToken(jtoken.Claims{Subject: "user-id", SessionID: "session-id"})
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	request.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: access})
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, request)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
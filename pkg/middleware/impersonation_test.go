@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"main/pkg/audit"
+	"main/pkg/jtoken"
+	"main/pkg/paging"
+	"main/pkg/redis/mocks"
+)
+
+// fakeAudit keeps the recorded events in memory.
+type fakeAudit struct {
+	events []*audit.Event
+}
+
+func (f *fakeAudit) Record(ctx context.Context, event *audit.Event) {
+	f.events = append(f.events, event)
+}
+
+func (f *fakeAudit) List(ctx context.Context, filter *audit.Filter) ([]*audit.Event, *paging.Pagination, error) {
+	return f.events, nil, nil
+}
+
+func (f *fakeAudit) Purge(ctx context.Context, before time.Time) (int64, error) { return 0, nil }
+
+// adminSessionCache only knows the session of the admin.
+func adminSessionCache(t *testing.T) *mocks.IRedis {
+	cache := mocks.NewIRedis(t)
+	cache.On("Get", "session:admin-id:session-id", mock.Anything).Return(nil).Maybe()
+	cache.On("Get", mock.Anything, mock.Anything).Return(errors.New("redis: nil")).Maybe()
+	return cache
+}
+
+func impersonationToken(readOnly bool) string {
+	return jtoken.GenerateImpersonationToken(jtoken.Claims{
+		Subject:   "user-id",
+		SessionID: "session-id",
+		Actor:     &jtoken.Actor{Subject: "admin-id"},
+		ReadOnly:  readOnly,
+	})
+}
+
+func TestJWTAuth_Impersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		token    string
+		method   string
+		code     int
+		recorded string
+	}{
+		{
+			name:     "read",
+			token:    impersonationToken(false),
+			method:   http.MethodGet,
+			code:     http.StatusOK,
+			recorded: audit.OutcomeSuccess,
+		},
+		{
+			name:     "write",
+			token:    impersonationToken(false),
+			method:   http.MethodPut,
+			code:     http.StatusOK,
+			recorded: audit.OutcomeSuccess,
+		},
+		{
+			name:     "read only read",
+			token:    impersonationToken(true),
+			method:   http.MethodGet,
+			code:     http.StatusOK,
+			recorded: audit.OutcomeSuccess,
+		},
+		{
+			name:     "read only write",
+			token:    impersonationToken(true),
+			method:   http.MethodPut,
+			code:     http.StatusForbidden,
+			recorded: audit.OutcomeFailure,
+		},
+		{
+			name: "unknown admin session",
+			token: jtoken.GenerateImpersonationToken(jtoken.Claims{
+				Subject:   "user-id",
+				SessionID: "user-session-id",
+				Actor:     &jtoken.Actor{Subject: "admin-id"},
+			}),
+			method: http.MethodGet,
+			code:   http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &fakeAudit{}
+			router := gin.New()
+			router.Use(AuditImpersonation(log))
+			router.Handle(tt.method, "/me", JWTAuth(adminSessionCache(t)), func(c *gin.Context) {
+				principal, _ := GetPrincipal(c)
+				assert.Equal(t, "user-id", principal.UserID)
+				assert.Equal(t, "admin-id", principal.ActorID)
+				c.Status(http.StatusOK)
+			})
+
+			request, _ := http.NewRequest(tt.method, "/me", nil)
+			request.Header.Set("Authorization", "Bearer "+tt.token)
+			writer := httptest.NewRecorder()
+			router.ServeHTTP(writer, request)
+			assert.Equal(t, tt.code, writer.Code)
+
+			if tt.recorded == "" {
+				assert.Empty(t, log.events)
+				return
+			}
+			require.Len(t, log.events, 1)
+			assert.Equal(t, audit.EventImpersonated, log.events[0].Type)
+			assert.Equal(t, tt.recorded, log.events[0].Outcome)
+			assert.Equal(t, "admin-id", log.events[0].ActorID)
+			assert.Equal(t, "user-id", log.events[0].UserID)
+			assert.Equal(t, tt.method+" /me", log.events[0].Resource)
+		})
+	}
+}
+
+func TestAuditImpersonation_IgnoresOwnToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cache := mocks.NewIRedis(t)
+	cache.On("Get", mock.Anything, mock.Anything).Return(nil)
+	log := &fakeAudit{}
+	router := gin.New()
+	router.Use(AuditImpersonation(log))
+	router.GET("/me", JWTAuth(cache), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	token := jtoken.GenerateAccessToken(jtoken.Claims{Subject: "user-id", SessionID: "session-id"})
+	request, _ := http.NewRequest(http.MethodGet, "/me", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, request)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Empty(t, log.events)
+}
+
+func TestImpersonationInterceptor(t *testing.T) {
+	auth := NewAuthInterceptor(nil, nil, nil, nil, adminSessionCache(t), nil)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name     string
+		readOnly bool
+		method   string
+		code     codes.Code
+		recorded string
+	}{
+		{
+			name:     "write",
+			method:   "/test.Service/Write",
+			code:     codes.OK,
+			recorded: audit.OutcomeSuccess,
+		},
+		{
+			name:     "read only read",
+			readOnly: true,
+			method:   "/test.Service/Read",
+			code:     codes.OK,
+			recorded: audit.OutcomeSuccess,
+		},
+		{
+			name:     "read only write",
+			readOnly: true,
+			method:   "/test.Service/Write",
+			code:     codes.PermissionDenied,
+			recorded: audit.OutcomeFailure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &fakeAudit{}
+			impersonation := NewImpersonationInterceptor([]string{"/test.Service/Read"}, log)
+
+			md := metadata.Pairs("authorization", "Bearer "+impersonationToken(tt.readOnly))
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+
+			_, err := auth.Unary()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return impersonation.Unary()(ctx, req, info, handler)
+			})
+			assert.Equal(t, tt.code, status.Code(err))
+
+			require.Len(t, log.events, 1)
+			assert.Equal(t, tt.recorded, log.events[0].Outcome)
+			assert.Equal(t, "admin-id", log.events[0].ActorID)
+			assert.Equal(t, tt.method, log.events[0].Resource)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+
+	"main/pkg/apikey"
+	"main/pkg/jtoken"
+)
+
+// principalKey is a plain string so that a *gin.Context finds the principal
+// through c.Get as well as gRPC contexts through context.WithValue.
+const principalKey = "principal"
+
+// Principal is the authenticated caller of a request. JWT and AuthInterceptor
+// attach the same value, so HTTP and gRPC handlers read it the same way.
+type Principal struct {
+	UserID    string
+	Email     string
+	Role      string
+	SessionID string
+	TokenID   string
+	TokenType string
+	// APIKeyID and Scopes are set when the caller used an API key instead of
+	// a token. Such a caller acts as UserID but only within Scopes.
+	APIKeyID string
+	Scopes   []string
+	// ActorID is the admin impersonating UserID, ReadOnly is set when the
+	// impersonation may not change anything.
+	ActorID  string
+	ReadOnly bool
+}
+
+func NewPrincipal(claims *jtoken.Claims) *Principal {
+	p := &Principal{
+		UserID:    claims.Subject,
+		Email:     claims.Email,
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
+		TokenID:   claims.ID,
+		TokenType: claims.Type,
+		ReadOnly:  claims.ReadOnly,
+	}
+	if claims.Actor != nil {
+		p.ActorID = claims.Actor.Subject
+	}
+	return p
+}
+
+func NewAPIKeyPrincipal(key *apikey.Key) *Principal {
+	return &Principal{
+		UserID:   key.UserID,
+		Role:     key.Role,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}
+}
+
+// SessionOwner returns the user whose session the caller uses, which is the
+// impersonating admin rather than UserID during an impersonation.
+func (p *Principal) SessionOwner() string {
+	if p.ActorID != "" {
+		return p.ActorID
+	}
+	return p.UserID
+}
+
+// HasScope reports whether p may act within scope. Callers using a token are
+// only limited by their role.
+func (p *Principal) HasScope(scope string) bool {
+	if p.APIKeyID == "" {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+// GetPrincipal returns the authenticated caller of ctx, which can be a
+// *gin.Context or the context of a gRPC handler.
+func GetPrincipal(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey).(*Principal)
+	if !ok || p == nil || p.UserID == "" {
+		return nil, false
+	}
+
+	return p, true
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole lets a request through only if the role of the principal set by
+// JWT is one of roles. It must be mounted after JWTAuth.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, nil)
+			c.Abort()
+			return
+		}
+
+		if !hasRole(principal.Role, roles) {
+			c.JSON(http.StatusForbidden, nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope lets a request made with an API key through only if the key
+// was given scope. It must be mounted after JWTAuthOrAPIKey.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, nil)
+			c.Abort()
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			c.JSON(http.StatusForbidden, nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func hasRole(role string, roles []string) bool {
+	for _, r := range roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"main/pkg/jtoken"
+	"main/pkg/redis/mocks"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		userID string
+		role   string
+		code   int
+	}{
+		{
+			name:   "allowed role",
+			userID: "user-id",
+			role:   "admin",
+			code:   http.StatusOK,
+		},
+		{
+			name:   "denied role",
+			userID: "user-id",
+			role:   "customer",
+			code:   http.StatusForbidden,
+		},
+		{
+			name: "unauthenticated",
+			code: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				if tt.userID != "" {
+					c.Set(principalKey, &Principal{UserID: tt.userID, Role: tt.role})
+				}
+			}, RequireRole("admin"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			writer := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", "/", nil)
+			router.ServeHTTP(writer, request)
+			assert.Equal(t, tt.code, writer.Code)
+		})
+	}
+}
+
+func TestAuthInterceptor_MethodRoles(t *testing.T) {
+	cache := mocks.NewIRedis(t)
+	cache.On("Get", mock.Anything, mock.Anything).Return(nil)
+
+	interceptor := NewAuthInterceptor(nil, nil, map[string][]string{
+		"/test.Service/Admin": {"admin"},
+	}, nil, cache, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Admin"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name string
+		role string
+		code codes.Code
+	}{
+		{
+			name: "allowed role",
+			role: "admin",
+			code: codes.OK,
+		},
+		{
+			name: "denied role",
+			role: "customer",
+			code: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jtoken.GenerateAccessToken(jtoken.Claims{
+				Subject:   "user-id",
+				SessionID: "session-id",
+				Role:      tt.role,
+			})
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+
+			_, err := interceptor.Unary()(ctx, nil, info, handler)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,456 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"main/pkg/config"
+	"main/pkg/jtoken"
+	"main/pkg/redis"
+	"main/pkg/utils"
+)
+
+var (
+	ErrInvalidState     = errors.New("invalid or expired oidc state")
+	ErrExchangeFailed   = errors.New("oidc code exchange failed")
+	ErrInvalidIDToken   = errors.New("invalid oidc id token")
+	ErrUnverifiedEmail  = errors.New("oidc email is not verified")
+	ErrDiscoveryFailed  = errors.New("oidc discovery failed")
+	ErrUnknownSigningID = errors.New("unknown oidc signing key")
+)
+
+// Config describes the client registered with the identity provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Identity is the user the identity provider vouched for.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+//go:generate mockery --name=IProvider
+type IProvider interface {
+	AuthCodeURL(ctx context.Context) (string, error)
+	Exchange(ctx context.Context, code, state string) (*Identity, error)
+}
+
+// Provider runs the authorization code flow with PKCE against an OpenID
+// Connect identity provider. The state of a started flow is kept in redis
+// until its callback, so any instance can handle the callback.
+type Provider struct {
+	cfg    Config
+	cache  redis.IRedis
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]crypto.PublicKey
+	// keysFetchedAt is when keys were last fetched, unknown key ids do not
+	// refetch them more often than every config.OIDCKeysRefreshInterval.
+	keysFetchedAt time.Time
+}
+
+// discovery is the part of the provider metadata used by the flow.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// authRequest is what is kept between the redirect and the callback.
+type authRequest struct {
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
+
+func New(cfg Config, cache redis.IRedis) *Provider {
+	return &Provider{
+		cfg:    cfg,
+		cache:  cache,
+		client: &http.Client{Timeout: config.OIDCHTTPTimeout},
+	}
+}
+
+// NewFromConfig returns the provider configured in cfg, or nil when no
+// issuer is configured.
+func NewFromConfig(cfg *config.Schema, cache redis.IRedis) IProvider {
+	if cfg.OIDCIssuer == "" {
+		return nil
+	}
+
+	return New(Config{
+		Issuer:       cfg.OIDCIssuer,
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCClientSecret,
+		RedirectURL:  cfg.OIDCRedirectURL,
+		Scopes:       cfg.OIDCScopes,
+	}, cache)
+}
+
+func stateKey(state string) string {
+	return fmt.Sprintf("oidc:state:%s", state)
+}
+
+// AuthCodeURL starts a login and returns the URL of the identity provider to
+// send the user to.
+func (p *Provider) AuthCodeURL(ctx context.Context) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := utils.GenerateToken(config.OIDCTokenSize)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := utils.GenerateToken(config.OIDCTokenSize)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := utils.GenerateToken(config.OIDCTokenSize)
+	if err != nil {
+		return "", err
+	}
+
+	req := authRequest{Verifier: verifier, Nonce: nonce}
+	if err = p.cache.SetWithExpiration(stateKey(state), req, config.OIDCStateExpiredTime); err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return d.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange finishes the login started with state. It trades code for an id
+// token and returns the identity in it. A state can only be used once.
+func (p *Provider) Exchange(ctx context.Context, code, state string) (*Identity, error) {
+	var req authRequest
+	// The state is taken in one step, so that two callbacks with the same
+	// state cannot both get it.
+	if state == "" || p.cache.GetDel(stateKey(state), &req) != nil {
+		return nil, ErrInvalidState
+	}
+
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := p.exchangeCode(ctx, d, code, req.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.verifyIDToken(ctx, d, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Nonce != req.Nonce {
+		return nil, ErrInvalidIDToken
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *Provider) exchangeCode(ctx context.Context, d *discovery, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrExchangeFailed, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.IDToken == "" {
+		return "", fmt.Errorf("%w: %s %s", ErrExchangeFailed, body.Error, body.ErrorDescription)
+	}
+
+	return body.IDToken, nil
+}
+
+// idTokenClaims are the claims of an id token used for the login.
+type idTokenClaims struct {
+	Issuer        string          `json:"iss"`
+	Subject       string          `json:"sub"`
+	Audience      jtoken.Audience `json:"aud"`
+	ExpiresAt     int64           `json:"exp"`
+	IssuedAt      int64           `json:"iat"`
+	Nonce         string          `json:"nonce"`
+	Email         string          `json:"email"`
+	EmailVerified flexibleBool    `json:"email_verified"`
+	Name          string          `json:"name"`
+}
+
+// Valid is called by the jwt parser and checks the times of the token. The
+// issuer and audience are checked by verifyIDToken.
+func (c *idTokenClaims) Valid() error {
+	now := time.Now()
+	skew := config.GetConfig().JWTClockSkew
+	if c.ExpiresAt == 0 || now.Add(-skew).Unix() >= c.ExpiresAt {
+		return jtoken.ErrTokenExpired
+	}
+	if now.Add(skew).Unix() < c.IssuedAt {
+		return jtoken.ErrTokenIssuedLater
+	}
+	return nil
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, d *discovery, raw string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := p.getKey(ctx, d, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		switch key.(type) {
+		case *rsa.PublicKey:
+			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+				return nil, ErrInvalidIDToken
+			}
+		case *ecdsa.PublicKey:
+			if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+				return nil, ErrInvalidIDToken
+			}
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIDToken, err)
+	}
+
+	if claims.Issuer != d.Issuer {
+		return nil, ErrInvalidIDToken
+	}
+
+	audienceOK := false
+	for _, aud := range claims.Audience {
+		if aud == p.cfg.ClientID {
+			audienceOK = true
+		}
+	}
+	if !audienceOK || claims.Subject == "" {
+		return nil, ErrInvalidIDToken
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var d discovery
+	wellKnown := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, &d); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDiscoveryFailed, err)
+	}
+
+	if d.Issuer != p.cfg.Issuer || d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, ErrDiscoveryFailed
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// getKey returns the signing key kid of the provider. The keys are fetched
+// again once when kid is unknown, as providers rotate their keys.
+func (p *Provider) getKey(ctx context.Context, d *discovery, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	// Tokens with made up key ids must not make every call fetch the keys.
+	if time.Since(p.keysFetchedAt) < config.OIDCKeysRefreshInterval {
+		return nil, ErrUnknownSigningID
+	}
+
+	var set jwks
+	if err := p.getJSON(ctx, d.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	p.keysFetchedAt = time.Now()
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	p.keys = keys
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, ErrUnknownSigningID
+	}
+	return key, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", endpoint, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+// flexibleBool reads booleans that some providers, such as Apple, send as
+// strings.
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(data, &value); err == nil {
+		*b = flexibleBool(value)
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*b = flexibleBool(text == "true")
+	return nil
+}
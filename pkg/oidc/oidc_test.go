@@ -0,0 +1,220 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"main/pkg/oidc/oidctest"
+)
+
+// memoryCache is the part of redis.IRedis used by the provider, kept in
+// memory without expiry.
+type memoryCache struct {
+	values map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string][]byte)}
+}
+
+func (m *memoryCache) IsConnected() bool { return true }
+
+func (m *memoryCache) Get(key string, value interface{}) error {
+	data, ok := m.values[key]
+	if !ok {
+		return errors.New("redis: nil")
+	}
+	return json.Unmarshal(data, value)
+}
+
+func (m *memoryCache) GetDel(key string, value interface{}) error {
+	if err := m.Get(key, value); err != nil {
+		return err
+	}
+	delete(m.values, key)
+	return nil
+}
+
+func (m *memoryCache) Set(key string, value interface{}) error {
+	return m.SetWithExpiration(key, value, 0)
+}
+
+func (m *memoryCache) SetWithExpiration(key string, value interface{}, _ time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m.values[key] = data
+	return nil
+}
+
+func (m *memoryCache) Incr(string, time.Duration) (int64, error) { return 0, nil }
+
+func (m *memoryCache) Remove(keys ...string) error {
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+func (m *memoryCache) Keys(string) ([]string, error) { return nil, nil }
+
+func (m *memoryCache) RemovePattern(string) error { return nil }
+
+func (m *memoryCache) Eval(string, []string, ...interface{}) (interface{}, error) {
+	return nil, errors.New("not supported")
+}
+
+const redirectURL = "http://localhost/api/v1/auth/oidc/callback"
+
+func newTestProvider(t *testing.T, secret string) (*Provider, *oidctest.Server) {
+	idp := oidctest.NewServer("client", "secret")
+	t.Cleanup(idp.Close)
+	idp.SetUser(oidctest.User{
+		Subject:       "subject",
+		Email:         "oidc@test.com",
+		EmailVerified: true,
+		Name:          "OIDC User",
+	})
+
+	provider := New(Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     "client",
+		ClientSecret: secret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email"},
+	}, newMemoryCache())
+	return provider, idp
+}
+
+// authorize follows the login url to the provider and returns the code and
+// state it sends back to the redirect url.
+func authorize(t *testing.T, provider *Provider) (string, string) {
+	authURL, err := provider.AuthCodeURL(context.Background())
+	require.NoError(t, err)
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	provider, idp := newTestProvider(t, "secret")
+
+	authURL, err := provider.AuthCodeURL(context.Background())
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, idp.Issuer()+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "client", query.Get("client_id"))
+	assert.Equal(t, redirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.NotEmpty(t, query.Get("code_challenge"))
+	assert.NotEmpty(t, query.Get("state"))
+	assert.NotEmpty(t, query.Get("nonce"))
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	provider, _ := newTestProvider(t, "secret")
+	code, state := authorize(t, provider)
+
+	identity, err := provider.Exchange(context.Background(), code, state)
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{
+		Subject:       "subject",
+		Email:         "oidc@test.com",
+		EmailVerified: true,
+		Name:          "OIDC User",
+	}, identity)
+}
+
+func TestProvider_ExchangeStateUsedOnce(t *testing.T) {
+	provider, _ := newTestProvider(t, "secret")
+	code, state := authorize(t, provider)
+
+	_, err := provider.Exchange(context.Background(), code, state)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, state)
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestProvider_ExchangeUnknownState(t *testing.T) {
+	provider, _ := newTestProvider(t, "secret")
+	code, _ := authorize(t, provider)
+
+	_, err := provider.Exchange(context.Background(), code, "unknown")
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestProvider_ExchangeWrongSecret(t *testing.T) {
+	provider, _ := newTestProvider(t, "wrong")
+	code, state := authorize(t, provider)
+
+	_, err := provider.Exchange(context.Background(), code, state)
+	assert.ErrorIs(t, err, ErrExchangeFailed)
+}
+
+func TestProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	idp := oidctest.NewServer("client", "secret")
+	defer idp.Close()
+
+	provider := New(Config{Issuer: idp.Issuer() + "/", ClientID: "client"}, newMemoryCache())
+	_, err := provider.AuthCodeURL(context.Background())
+	assert.ErrorIs(t, err, ErrDiscoveryFailed)
+}
+
+func TestProvider_UnknownKeyRefetchLimited(t *testing.T) {
+	fetches := 0
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer jwksServer.Close()
+
+	provider := New(Config{}, newMemoryCache())
+	d := &discovery{JWKSURI: jwksServer.URL}
+	for i := 0; i < 3; i++ {
+		_, err := provider.getKey(context.Background(), d, "unknown")
+		assert.ErrorIs(t, err, ErrUnknownSigningID)
+	}
+	assert.Equal(t, 1, fetches)
+}
+
+func TestFlexibleBool(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{data: `true`, want: true},
+		{data: `false`, want: false},
+		{data: `"true"`, want: true},
+		{data: `"false"`, want: false},
+	}
+
+	for _, test := range tests {
+		var value flexibleBool
+		require.NoError(t, json.Unmarshal([]byte(test.data), &value))
+		assert.Equal(t, test.want, bool(value), test.data)
+	}
+}
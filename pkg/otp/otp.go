@@ -0,0 +1,185 @@
+package otp
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"main/pkg/redis"
+	"main/pkg/sms"
+	"main/pkg/throttle"
+	"main/pkg/utils"
+)
+
+var ErrInvalidCode = errors.New("invalid or expired code")
+
+// Policy describes the codes sent by an OTP.
+type Policy struct {
+	Length      int
+	ExpiresIn   time.Duration
+	MaxAttempts int
+}
+
+// IOTP interface
+//
+//go:generate mockery --name=IOTP
+type IOTP interface {
+	Reserve(phone, ip string) error
+	Send(phone string) error
+	Verify(phone, ip, code string) error
+}
+
+// OTP sends one-time codes by SMS and keeps them in redis until they are
+// used, expire or were guessed at too often. Sends are rate limited per phone
+// and per client IP, failed verifications per client IP.
+type OTP struct {
+	cache          redis.IRedis
+	sender         sms.SMSSender
+	phoneThrottle  throttle.IThrottle
+	ipThrottle     throttle.IThrottle
+	verifyThrottle throttle.IThrottle
+	policy         Policy
+}
+
+func New(
+	cache redis.IRedis,
+	sender sms.SMSSender,
+	phoneThrottle throttle.IThrottle,
+	ipThrottle throttle.IThrottle,
+	verifyThrottle throttle.IThrottle,
+	policy Policy) *OTP {
+	return &OTP{
+		cache:          cache,
+		sender:         sender,
+		phoneThrottle:  phoneThrottle,
+		ipThrottle:     ipThrottle,
+		verifyThrottle: verifyThrottle,
+		policy:         policy,
+	}
+}
+
+type code struct {
+	Hash      string    `json:"hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func codeKey(phone string) string {
+	return fmt.Sprintf("otp:code:%s", phone)
+}
+
+// attemptsKey counts the guesses at the pending code of phone. It is kept
+// apart from the code so that concurrent guesses are counted atomically.
+func attemptsKey(phone string) string {
+	return fmt.Sprintf("otp:attempts:%s", phone)
+}
+
+// Reserve counts a request for a code to phone from ip, and returns a
+// *throttle.LockedError instead while either may not request another code
+// yet. Callers reserve before they know whether phone is registered, so that
+// unknown phones are limited the same way.
+func (o *OTP) Reserve(phone, ip string) error {
+	if err := o.phoneThrottle.Check(phone); err != nil {
+		return err
+	}
+	if ip != "" {
+		if err := o.ipThrottle.Check(ip); err != nil {
+			return err
+		}
+	}
+
+	// Every send counts, so that resending waits longer each time.
+	if err := o.phoneThrottle.Fail(phone); err != nil {
+		return err
+	}
+	if ip != "" {
+		if err := o.ipThrottle.Fail(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Send replaces any pending code of phone with a new one. A request for it
+// must have been counted with Reserve.
+func (o *OTP) Send(phone string) error {
+	value, err := utils.GenerateNumericCode(o.policy.Length)
+	if err != nil {
+		return err
+	}
+
+	c := code{
+		Hash:      hashCode(phone, value),
+		ExpiresAt: time.Now().Add(o.policy.ExpiresIn),
+	}
+	if err = o.cache.SetWithExpiration(codeKey(phone), c, o.policy.ExpiresIn); err != nil {
+		return err
+	}
+	if err = o.cache.Remove(attemptsKey(phone)); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Your login code is %s. It expires in %d minutes.", value, int(o.policy.ExpiresIn.Minutes()))
+	return o.sender.Send(phone, message)
+}
+
+// Verify consumes the pending code of phone. A code is dropped once it was
+// tried MaxAttempts times. It returns a *throttle.LockedError
+// while ip failed too often to try another code.
+func (o *OTP) Verify(phone, ip, value string) error {
+	if ip != "" {
+		if err := o.verifyThrottle.Check(ip); err != nil {
+			return err
+		}
+	}
+
+	if err := o.verify(phone, value); err != nil {
+		if ip != "" && errors.Is(err, ErrInvalidCode) {
+			if failErr := o.verifyThrottle.Fail(ip); failErr != nil {
+				return failErr
+			}
+		}
+		return err
+	}
+
+	if err := o.cache.Remove(codeKey(phone), attemptsKey(phone)); err != nil {
+		return err
+	}
+
+	// A successful login makes waiting for the next code pointless.
+	return o.phoneThrottle.Reset(phone)
+}
+
+func (o *OTP) verify(phone, value string) error {
+	var c code
+	if err := o.cache.Get(codeKey(phone), &c); err != nil {
+		return ErrInvalidCode
+	}
+
+	ttl := time.Until(c.ExpiresAt)
+	if ttl <= 0 {
+		_ = o.cache.Remove(codeKey(phone), attemptsKey(phone))
+		return ErrInvalidCode
+	}
+
+	// Every attempt is counted before the code is compared, so that parallel
+	// guesses cannot get past MaxAttempts.
+	attempts, err := o.cache.Incr(attemptsKey(phone), ttl)
+	if err != nil {
+		return err
+	}
+	if attempts > int64(o.policy.MaxAttempts) {
+		_ = o.cache.Remove(codeKey(phone), attemptsKey(phone))
+		return ErrInvalidCode
+	}
+
+	if subtle.ConstantTimeCompare([]byte(c.Hash), []byte(hashCode(phone, value))) != 1 {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+// hashCode binds the code to the phone it was sent to.
+func hashCode(phone, value string) string {
+	return utils.HashToken(phone + ":" + value)
+}
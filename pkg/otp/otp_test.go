@@ -0,0 +1,172 @@
+package otp
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"main/pkg/throttle"
+)
+
+// memoryCache is the part of redis.IRedis used by OTP and throttle, kept in
+// memory without expiry.
+type memoryCache struct {
+	values map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string][]byte)}
+}
+
+func (m *memoryCache) IsConnected() bool { return true }
+
+func (m *memoryCache) Get(key string, value interface{}) error {
+	data, ok := m.values[key]
+	if !ok {
+		return errors.New("redis: nil")
+	}
+	return json.Unmarshal(data, value)
+}
+
+func (m *memoryCache) GetDel(key string, value interface{}) error {
+	if err := m.Get(key, value); err != nil {
+		return err
+	}
+	delete(m.values, key)
+	return nil
+}
+
+func (m *memoryCache) Set(key string, value interface{}) error {
+	return m.SetWithExpiration(key, value, 0)
+}
+
+func (m *memoryCache) SetWithExpiration(key string, value interface{}, _ time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m.values[key] = data
+	return nil
+}
+
+func (m *memoryCache) Incr(key string, _ time.Duration) (int64, error) {
+	var count int64
+	_ = m.Get(key, &count)
+	count++
+	return count, m.Set(key, count)
+}
+
+func (m *memoryCache) Remove(keys ...string) error {
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+func (m *memoryCache) Keys(string) ([]string, error) { return nil, nil }
+
+func (m *memoryCache) RemovePattern(string) error { return nil }
+
+func (m *memoryCache) Eval(string, []string, ...interface{}) (interface{}, error) {
+	return nil, errors.New("not supported")
+}
+
+type sentMessages []string
+
+func (s *sentMessages) Send(_, message string) error {
+	*s = append(*s, message)
+	return nil
+}
+
+var codePattern = regexp.MustCompile(`\d{6}`)
+
+func newTestOTP(t *testing.T) (*OTP, *sentMessages) {
+	cache := newMemoryCache()
+	sent := &sentMessages{}
+	phoneThrottle := throttle.New(cache, "otp:phone", throttle.Policy{MaxAttempts: 3, Delay: time.Minute, Lockout: time.Hour})
+	ipThrottle := throttle.New(cache, "otp:ip", throttle.Policy{MaxAttempts: 10, Lockout: time.Hour})
+	verifyThrottle := throttle.New(cache, "otp:verify:ip", throttle.Policy{MaxAttempts: 3, Lockout: time.Hour})
+	policy := Policy{Length: 6, ExpiresIn: 5 * time.Minute, MaxAttempts: 2}
+	return New(cache, sent, phoneThrottle, ipThrottle, verifyThrottle, policy), sent
+}
+
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func lastCode(t *testing.T, sent *sentMessages) string {
+	require.NotEmpty(t, *sent)
+	code := codePattern.FindString((*sent)[len(*sent)-1])
+	require.NotEmpty(t, code)
+	return code
+}
+
+func TestOTP_SendAndVerify(t *testing.T) {
+	o, sent := newTestOTP(t)
+
+	require.NoError(t, o.Send("+14155550100"))
+	code := lastCode(t, sent)
+
+	assert.ErrorIs(t, o.Verify("+14155550101", "", code), ErrInvalidCode)
+	assert.NoError(t, o.Verify("+14155550100", "", code))
+	assert.ErrorIs(t, o.Verify("+14155550100", "", code), ErrInvalidCode)
+}
+
+func TestOTP_VerifyTooManyAttempts(t *testing.T) {
+	o, sent := newTestOTP(t)
+
+	require.NoError(t, o.Send("+14155550100"))
+	code := lastCode(t, sent)
+
+	wrong := wrongCode(code)
+	assert.ErrorIs(t, o.Verify("+14155550100", "", wrong), ErrInvalidCode)
+	assert.ErrorIs(t, o.Verify("+14155550100", "", wrong), ErrInvalidCode)
+	assert.ErrorIs(t, o.Verify("+14155550100", "", code), ErrInvalidCode)
+}
+
+func TestOTP_ResendResetsAttempts(t *testing.T) {
+	o, sent := newTestOTP(t)
+
+	require.NoError(t, o.Send("+14155550100"))
+	wrong := wrongCode(lastCode(t, sent))
+	assert.ErrorIs(t, o.Verify("+14155550100", "", wrong), ErrInvalidCode)
+	assert.ErrorIs(t, o.Verify("+14155550100", "", wrong), ErrInvalidCode)
+
+	require.NoError(t, o.Send("+14155550100"))
+	assert.NoError(t, o.Verify("+14155550100", "", lastCode(t, sent)))
+}
+
+func TestOTP_VerifyLocksOutIP(t *testing.T) {
+	o, sent := newTestOTP(t)
+
+	require.NoError(t, o.Send("+14155550100"))
+	code := lastCode(t, sent)
+
+	for _, phone := range []string{"+14155550101", "+14155550102", "+14155550103"} {
+		assert.ErrorIs(t, o.Verify(phone, "127.0.0.1", code), ErrInvalidCode)
+	}
+
+	var locked *throttle.LockedError
+	assert.ErrorAs(t, o.Verify("+14155550100", "127.0.0.1", code), &locked)
+	assert.NoError(t, o.Verify("+14155550100", "127.0.0.2", code))
+}
+
+func TestOTP_SendRateLimited(t *testing.T) {
+	o, _ := newTestOTP(t)
+
+	require.NoError(t, o.Reserve("+14155550100", ""))
+	require.NoError(t, o.Reserve("+14155550100", ""))
+
+	var locked *throttle.LockedError
+	assert.ErrorAs(t, o.Reserve("+14155550100", ""), &locked)
+	assert.Equal(t, time.Minute, locked.RetryAfter.Round(time.Minute))
+	assert.NoError(t, o.Reserve("+14155550101", ""))
+}
@@ -0,0 +1,218 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"main/pkg/config"
+)
+
+const (
+	Bcrypt   = "bcrypt"
+	Argon2id = "argon2id"
+
+	argon2SaltSize = 16
+	argon2KeySize  = 32
+)
+
+var (
+	ErrMismatchedPassword = errors.New("password does not match")
+	ErrUnknownAlgorithm   = errors.New("unknown password hash algorithm")
+	ErrInvalidHash        = errors.New("invalid password hash")
+)
+
+// Params selects the algorithm new hashes are made with and its cost.
+// Argon2Memory is in KiB.
+type Params struct {
+	Algorithm     string
+	BcryptCost    int
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
+}
+
+// Hasher hashes passwords with one algorithm and cost. Hashes of any
+// supported algorithm are verified with Compare.
+type Hasher struct {
+	params Params
+}
+
+func New(params Params) (*Hasher, error) {
+	switch params.Algorithm {
+	case Bcrypt:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case Argon2id:
+		if params.Argon2Time == 0 || params.Argon2Memory == 0 || params.Argon2Threads == 0 {
+			return nil, errors.New("argon2id time, memory and threads must be positive")
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, params.Algorithm)
+	}
+
+	return &Hasher{params: params}, nil
+}
+
+// NewFromConfig builds a Hasher from the password_hash_* settings.
+func NewFromConfig(cfg *config.Schema) (*Hasher, error) {
+	return New(Params{
+		Algorithm:     cfg.PasswordHashAlgorithm,
+		BcryptCost:    cfg.PasswordBcryptCost,
+		Argon2Time:    uint32(cfg.PasswordArgon2Time),
+		Argon2Memory:  uint32(cfg.PasswordArgon2Memory),
+		Argon2Threads: uint8(cfg.PasswordArgon2Threads),
+	})
+}
+
+// Hash hashes password with the configured Hasher.
+func Hash(password string) (string, error) {
+	hasher, err := NewFromConfig(config.GetConfig())
+	if err != nil {
+		return "", err
+	}
+	return hasher.Hash(password)
+}
+
+// NeedsRehash reports whether hash was not made with the configured
+// algorithm and cost.
+func NeedsRehash(hash string) bool {
+	hasher, err := NewFromConfig(config.GetConfig())
+	if err != nil {
+		return false
+	}
+	return hasher.NeedsRehash(hash)
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.params.Algorithm == Bcrypt {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.params.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashed), nil
+	}
+
+	salt := make([]byte, argon2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	p := argon2Params{
+		time:    h.params.Argon2Time,
+		memory:  h.params.Argon2Memory,
+		threads: h.params.Argon2Threads,
+		salt:    salt,
+	}
+	p.key = argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, argon2KeySize)
+	return p.encode(), nil
+}
+
+// Compare checks password against a hash of any supported algorithm. It
+// returns ErrMismatchedPassword if it does not match and ErrInvalidHash if the
+// format of hash is not recognised.
+func Compare(hash, password string) error {
+	switch algorithm(hash) {
+	case Bcrypt:
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatchedPassword
+		}
+		return err
+	case Argon2id:
+		p, err := decodeArgon2(hash)
+		if err != nil {
+			return err
+		}
+
+		key := argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, uint32(len(p.key)))
+		if subtle.ConstantTimeCompare(key, p.key) != 1 {
+			return ErrMismatchedPassword
+		}
+		return nil
+	default:
+		return ErrInvalidHash
+	}
+}
+
+func (h *Hasher) NeedsRehash(hash string) bool {
+	if algorithm(hash) != h.params.Algorithm {
+		return true
+	}
+
+	if h.params.Algorithm == Bcrypt {
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != h.params.BcryptCost
+	}
+
+	p, err := decodeArgon2(hash)
+	return err != nil ||
+		p.time != h.params.Argon2Time ||
+		p.memory != h.params.Argon2Memory ||
+		p.threads != h.params.Argon2Threads
+}
+
+// algorithm detects the algorithm from the prefix of a stored hash.
+func algorithm(hash string) string {
+	switch {
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return Bcrypt
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return Argon2id
+	default:
+		return ""
+	}
+}
+
+type argon2Params struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+// encode formats the hash like the argon2 reference implementation:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func (p argon2Params) encode() string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		p.memory, p.time, p.threads,
+		base64.RawStdEncoding.EncodeToString(p.salt),
+		base64.RawStdEncoding.EncodeToString(p.key),
+	)
+}
+
+func decodeArgon2(hash string) (*argon2Params, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, ErrInvalidHash
+	}
+
+	var p argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return nil, ErrInvalidHash
+	}
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrInvalidHash
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(p.key) == 0 {
+		return nil, ErrInvalidHash
+	}
+
+	return &p, nil
+}
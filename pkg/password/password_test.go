@@ -0,0 +1,102 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	bcryptTestParams = Params{Algorithm: Bcrypt, BcryptCost: 4}
+	argon2TestParams = Params{Algorithm: Argon2id, Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 1}
+)
+
+func TestHasher_HashAndCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		prefix string
+	}{
+		{
+			name:   "bcrypt",
+			params: bcryptTestParams,
+			prefix: "$2a$04$",
+		},
+		{
+			name:   "argon2id",
+			params: argon2TestParams,
+			prefix: "$argon2id$v=19$m=1024,t=1,p=1$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher, err := New(tt.params)
+			require.NoError(t, err)
+
+			hash, err := hasher.Hash("test123456")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tt.prefix), hash)
+
+			assert.NoError(t, Compare(hash, "test123456"))
+			assert.ErrorIs(t, Compare(hash, "wrong123456"), ErrMismatchedPassword)
+			assert.False(t, hasher.NeedsRehash(hash))
+		})
+	}
+}
+
+func TestHasher_HashTooLong(t *testing.T) {
+	hasher, err := New(bcryptTestParams)
+	require.NoError(t, err)
+
+	hash, err := hasher.Hash(strings.Repeat("0123456789", 8))
+	assert.Error(t, err)
+	assert.Empty(t, hash)
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	bcryptHasher, err := New(bcryptTestParams)
+	require.NoError(t, err)
+	argon2Hasher, err := New(argon2TestParams)
+	require.NoError(t, err)
+	strongerArgon2, err := New(Params{Algorithm: Argon2id, Argon2Time: 2, Argon2Memory: 1024, Argon2Threads: 1})
+	require.NoError(t, err)
+	strongerBcrypt, err := New(Params{Algorithm: Bcrypt, BcryptCost: 5})
+	require.NoError(t, err)
+
+	bcryptHash, err := bcryptHasher.Hash("test123456")
+	require.NoError(t, err)
+	argon2Hash, err := argon2Hasher.Hash("test123456")
+	require.NoError(t, err)
+
+	assert.True(t, argon2Hasher.NeedsRehash(bcryptHash))
+	assert.True(t, bcryptHasher.NeedsRehash(argon2Hash))
+	assert.True(t, strongerArgon2.NeedsRehash(argon2Hash))
+	assert.True(t, strongerBcrypt.NeedsRehash(bcryptHash))
+	assert.True(t, argon2Hasher.NeedsRehash("plain"))
+}
+
+func TestCompare_InvalidHash(t *testing.T) {
+	tests := []string{
+		"",
+		"plain",
+		"$argon2id$v=19$m=1024,t=1,p=1$salt",
+		"$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=1,p=1$c2FsdA$a2V5",
+	}
+	for _, hash := range tests {
+		assert.ErrorIs(t, Compare(hash, "test123456"), ErrInvalidHash, hash)
+	}
+}
+
+func TestNew_InvalidParams(t *testing.T) {
+	_, err := New(Params{Algorithm: "md5"})
+	assert.ErrorIs(t, err, ErrUnknownAlgorithm)
+
+	_, err = New(Params{Algorithm: Bcrypt, BcryptCost: 1})
+	assert.Error(t, err)
+
+	_, err = New(Params{Algorithm: Argon2id})
+	assert.Error(t, err)
+}
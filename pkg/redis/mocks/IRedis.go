@@ -13,6 +13,35 @@ type IRedis struct {
 	mock.Mock
 }
 
+// Eval provides a mock function with given fields: script, keys, args
+func (_m *IRedis) Eval(script string, keys []string, args ...interface{}) (interface{}, error) {
+	var _ca []interface{}
+	_ca = append(_ca, script, keys)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []string, ...interface{}) (interface{}, error)); ok {
+		return rf(script, keys, args...)
+	}
+	if rf, ok := ret.Get(0).(func(string, []string, ...interface{}) interface{}); ok {
+		r0 = rf(script, keys, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []string, ...interface{}) error); ok {
+		r1 = rf(script, keys, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: key, value
 func (_m *IRedis) Get(key string, value interface{}) error {
 	ret := _m.Called(key, value)
@@ -27,6 +56,44 @@ func (_m *IRedis) Get(key string, value interface{}) error {
 	return r0
 }
 
+// GetDel provides a mock function with given fields: key, value
+func (_m *IRedis) GetDel(key string, value interface{}) error {
+	ret := _m.Called(key, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, interface{}) error); ok {
+		r0 = rf(key, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Incr provides a mock function with given fields: key, expiration
+func (_m *IRedis) Incr(key string, expiration time.Duration) (int64, error) {
+	ret := _m.Called(key, expiration)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration) (int64, error)); ok {
+		return rf(key, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Duration) int64); ok {
+		r0 = rf(key, expiration)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(key, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsConnected provides a mock function with given fields:
 func (_m *IRedis) IsConnected() bool {
 	ret := _m.Called()
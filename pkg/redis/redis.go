@@ -20,11 +20,14 @@ const (
 type IRedis interface {
 	IsConnected() bool
 	Get(key string, value interface{}) error
+	GetDel(key string, value interface{}) error
 	Set(key string, value interface{}) error
 	SetWithExpiration(key string, value interface{}, expiration time.Duration) error
+	Incr(key string, expiration time.Duration) (int64, error)
 	Remove(keys ...string) error
 	Keys(pattern string) ([]string, error)
 	RemovePattern(pattern string) error
+	Eval(script string, keys []string, args ...interface{}) (interface{}, error)
 }
 
 // Config redis
@@ -92,6 +95,20 @@ func (r *redis) Get(key string, value interface{}) error {
 	return nil
 }
 
+// GetDel reads the value at key and deletes it in one step, so that only one
+// caller gets it.
+func (r *redis) GetDel(key string, value interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
+	defer cancel()
+
+	strValue, err := r.cmd.GetDel(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(strValue), value)
+}
+
 func (r *redis) SetWithExpiration(key string, value interface{}, expiration time.Duration) error {
 	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
 	defer cancel()
@@ -118,6 +135,27 @@ func (r *redis) Set(key string, value interface{}) error {
 	return nil
 }
 
+// incrScript increments the counter at KEYS[1] and gives a new counter an
+// expiry of ARGV[1] milliseconds, in one step so that no counter is left
+// without one.
+const incrScript = `
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`
+
+// Incr increments the counter at key and returns the new value. The
+// expiration is set when the counter is created, so it counts within a fixed
+// window.
+func (r *redis) Incr(key string, expiration time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
+	defer cancel()
+
+	return r.cmd.Eval(ctx, incrScript, []string{key}, expiration.Milliseconds()).Int64()
+}
+
 func (r *redis) Remove(keys ...string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
 	defer cancel()
@@ -159,3 +197,11 @@ func (r *redis) RemovePattern(pattern string) error {
 
 	return nil
 }
+
+// Eval runs a Lua script, which redis executes atomically.
+func (r *redis) Eval(script string, keys []string, args ...interface{}) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
+	defer cancel()
+
+	return r.cmd.Eval(ctx, script, keys, args...).Result()
+}
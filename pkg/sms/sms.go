@@ -0,0 +1,55 @@
+package sms
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/config"
+)
+
+// SMSSender interface
+//
+//go:generate mockery --name=SMSSender
+type SMSSender interface {
+	Send(to, message string) error
+}
+
+// New returns the sender for cfg. Only the log sender exists so far; a
+// provider such as Twilio plugs in here behind an sms_driver setting.
+func New(cfg *config.Schema) SMSSender {
+	return NewLogSender(cfg.SMSLogFile)
+}
+
+// logSender writes messages to the application log and, when a path is set,
+// appends them to that file instead of delivering them.
+type logSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewLogSender(path string) SMSSender {
+	return &logSender{path: path}
+}
+
+func (s *logSender) Send(to, message string) error {
+	logger.Infof("SMS to: %s, message: %s", to, message)
+	if s.path == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), to, message)
+	return err
+}
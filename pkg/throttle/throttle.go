@@ -0,0 +1,195 @@
+package throttle
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"main/pkg/redis"
+)
+
+var ErrLocked = errors.New("too many failed attempts")
+
+// LockedError is returned while a key is locked out or has to wait before
+// the next attempt.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLocked, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Policy describes how failures of a key are punished. From the second
+// failure on the key has to wait Delay, doubled on every further failure.
+// Reaching MaxAttempts locks the key for Lockout, which is also how long
+// failures are remembered.
+type Policy struct {
+	MaxAttempts int
+	Delay       time.Duration
+	Lockout     time.Duration
+}
+
+// IThrottle interface
+//
+//go:generate mockery --name=IThrottle
+type IThrottle interface {
+	Check(key string) error
+	Reserve(key string) error
+	Release(key string) error
+	Fail(key string) error
+	Reset(key string) error
+}
+
+// Throttle keeps failure counters and lockouts in redis under prefix.
+type Throttle struct {
+	cache  redis.IRedis
+	prefix string
+	policy Policy
+}
+
+func New(cache redis.IRedis, prefix string, policy Policy) *Throttle {
+	return &Throttle{
+		cache:  cache,
+		prefix: prefix,
+		policy: policy,
+	}
+}
+
+// Check returns a *LockedError if key may not be tried yet.
+func (t *Throttle) Check(key string) error {
+	var until int64
+	if err := t.cache.Get(t.lockKey(key), &until); err != nil {
+		return nil
+	}
+
+	if wait := time.Until(time.UnixMilli(until)); wait > 0 {
+		return &LockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// reserveScript counts an attempt at KEYS[1] unless the lock at KEYS[2] is
+// still held, and takes the lock the count earns, see Throttle.wait. ARGV
+// holds MaxAttempts, Delay and Lockout, the durations in milliseconds, and
+// the current unix time in milliseconds. It returns the count, or the
+// remaining lock time negated.
+const reserveScript = `
+local ttl = redis.call('PTTL', KEYS[2])
+if ttl > 0 then
+	return -ttl
+end
+local maxAttempts, delay, lockout, now = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3]), tonumber(ARGV[4])
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], lockout)
+end
+local wait = 0
+if maxAttempts > 0 and count >= maxAttempts then
+	wait = lockout
+elseif count >= 2 and delay > 0 then
+	wait = math.min(delay * 2 ^ (count - 2), lockout)
+end
+if wait > 0 then
+	wait = math.floor(wait)
+	redis.call('SET', KEYS[2], now + wait, 'PX', wait)
+end
+return count
+`
+
+// Reserve counts an attempt for key before it is made, and returns a
+// *LockedError instead if key may not be tried yet. Unlike Check followed by
+// Fail it is one atomic step, so parallel attempts cannot all get past a
+// lockout that the first of them earns. A successful attempt should Reset.
+func (t *Throttle) Reserve(key string) error {
+	result, err := t.cache.Eval(
+		reserveScript,
+		[]string{t.countKey(key), t.lockKey(key)},
+		t.policy.MaxAttempts,
+		t.policy.Delay.Milliseconds(),
+		t.policy.Lockout.Milliseconds(),
+		time.Now().UnixMilli(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if remaining, ok := result.(int64); ok && remaining < 0 {
+		return &LockedError{RetryAfter: time.Duration(-remaining) * time.Millisecond}
+	}
+	return nil
+}
+
+// releaseScript takes back one attempt counted at KEYS[1], if any.
+const releaseScript = `
+if tonumber(redis.call('GET', KEYS[1]) or '0') > 0 then
+	redis.call('DECR', KEYS[1])
+end
+return 0
+`
+
+// Release takes back an attempt counted by Reserve that turned out to be
+// successful. A delay the attempt started is kept.
+func (t *Throttle) Release(key string) error {
+	_, err := t.cache.Eval(releaseScript, []string{t.countKey(key)})
+	return err
+}
+
+// Fail records a failed attempt for key and applies the delay or lockout it
+// earns.
+func (t *Throttle) Fail(key string) error {
+	count, err := t.cache.Incr(t.countKey(key), t.policy.Lockout)
+	if err != nil {
+		return err
+	}
+
+	wait := t.wait(count)
+	if wait <= 0 {
+		return nil
+	}
+
+	if count >= int64(t.policy.MaxAttempts) {
+		// Start counting from zero once the lockout is over.
+		if err = t.cache.Remove(t.countKey(key)); err != nil {
+			return err
+		}
+	}
+
+	return t.cache.SetWithExpiration(t.lockKey(key), time.Now().Add(wait).UnixMilli(), wait)
+}
+
+// Reset clears the failures and any lockout of key.
+func (t *Throttle) Reset(key string) error {
+	return t.cache.Remove(t.countKey(key), t.lockKey(key))
+}
+
+func (t *Throttle) wait(count int64) time.Duration {
+	if t.policy.MaxAttempts > 0 && count >= int64(t.policy.MaxAttempts) {
+		return t.policy.Lockout
+	}
+
+	if count < 2 || t.policy.Delay <= 0 {
+		return 0
+	}
+
+	wait := t.policy.Delay
+	for i := int64(2); i < count && wait < t.policy.Lockout; i++ {
+		wait *= 2
+	}
+	if wait > t.policy.Lockout {
+		wait = t.policy.Lockout
+	}
+	return wait
+}
+
+func (t *Throttle) countKey(key string) string {
+	return fmt.Sprintf("%s:count:%s", t.prefix, key)
+}
+
+func (t *Throttle) lockKey(key string) string {
+	return fmt.Sprintf("%s:lock:%s", t.prefix, key)
+}
@@ -0,0 +1,151 @@
+package throttle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"main/pkg/redis/mocks"
+)
+
+var testPolicy = Policy{
+	MaxAttempts: 5,
+	Delay:       time.Second,
+	Lockout:     time.Minute,
+}
+
+func TestThrottle_Wait(t *testing.T) {
+	tests := []struct {
+		count int64
+		wait  time.Duration
+	}{
+		{count: 1, wait: 0},
+		{count: 2, wait: time.Second},
+		{count: 3, wait: 2 * time.Second},
+		{count: 4, wait: 4 * time.Second},
+		{count: 5, wait: time.Minute},
+		{count: 9, wait: time.Minute},
+	}
+	throttle := New(nil, "test", testPolicy)
+	for _, tt := range tests {
+		assert.Equal(t, tt.wait, throttle.wait(tt.count), "count %d", tt.count)
+	}
+}
+
+func TestThrottle_WaitCappedByLockout(t *testing.T) {
+	throttle := New(nil, "test", Policy{MaxAttempts: 100, Delay: time.Second, Lockout: 10 * time.Second})
+	assert.Equal(t, 10*time.Second, throttle.wait(50))
+}
+
+func TestThrottle_Fail(t *testing.T) {
+	tests := []struct {
+		name   string
+		count  int64
+		delay  bool
+		remove bool
+	}{
+		{
+			name:  "first failure",
+			count: 1,
+		},
+		{
+			name:  "progressive delay",
+			count: 3,
+			delay: true,
+		},
+		{
+			name:   "lockout",
+			count:  5,
+			delay:  true,
+			remove: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewIRedis(t)
+			cache.On("Incr", "test:count:key", time.Minute).Return(tt.count, nil)
+			if tt.remove {
+				cache.On("Remove", "test:count:key").Return(nil)
+			}
+			if tt.delay {
+				cache.On("SetWithExpiration", "test:lock:key", mock.Anything, New(nil, "", testPolicy).wait(tt.count)).Return(nil)
+			}
+
+			err := New(cache, "test", testPolicy).Fail("key")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestThrottle_Check(t *testing.T) {
+	tests := []struct {
+		name  string
+		until time.Time
+		err   error
+		want  error
+	}{
+		{
+			name: "no lock",
+			err:  errors.New("redis: nil"),
+		},
+		{
+			name:  "locked",
+			until: time.Now().Add(time.Minute),
+			want:  ErrLocked,
+		},
+		{
+			name:  "lock over",
+			until: time.Now().Add(-time.Second),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewIRedis(t)
+			cache.On("Get", "test:lock:key", mock.Anything).Run(func(args mock.Arguments) {
+				*args.Get(1).(*int64) = tt.until.UnixMilli()
+			}).Return(tt.err)
+
+			err := New(cache, "test", testPolicy).Check("key")
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+
+			var locked *LockedError
+			assert.ErrorAs(t, err, &locked)
+			assert.True(t, locked.RetryAfter > 0)
+		})
+	}
+}
+
+func TestThrottle_Reserve(t *testing.T) {
+	tests := []struct {
+		name   string
+		result interface{}
+		want   error
+	}{
+		{name: "counted", result: int64(3)},
+		{name: "locked", result: int64(-1500), want: ErrLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewIRedis(t)
+			cache.On("Eval", reserveScript, []string{"test:count:key", "test:lock:key"},
+				5, int64(1000), int64(60000), mock.Anything).Return(tt.result, nil)
+
+			err := New(cache, "test", testPolicy).Reserve("key")
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var locked *LockedError
+			assert.ErrorAs(t, err, &locked)
+			assert.Equal(t, 1500*time.Millisecond, locked.RetryAfter)
+		})
+	}
+}
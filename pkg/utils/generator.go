@@ -1,7 +1,9 @@
 package utils
 
 import (
+	crand "crypto/rand"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"strings"
 	"time"
@@ -49,3 +51,17 @@ func stringWithCharset(length int) string {
 func genStringWithLength(length int) string {
 	return stringWithCharset(length)
 }
+
+// GenerateNumericCode returns a cryptographically random code made of the
+// given number of digits, suitable for one-time verification codes.
+func GenerateNumericCode(length int) (string, error) {
+	b := make([]byte, length)
+	for i := range b {
+		n, err := crand.Int(crand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b[i] = byte('0' + n.Int64())
+	}
+	return string(b), nil
+}
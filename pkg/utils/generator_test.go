@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestGenerateNumericCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+	}{
+		{
+			name:   "six digits",
+			length: 6,
+		},
+		{
+			name:   "empty",
+			length: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateNumericCode(tt.length)
+			if err != nil {
+				t.Fatalf("GenerateNumericCode() error = %v", err)
+			}
+			if len(got) != tt.length {
+				t.Errorf("GenerateNumericCode() = %v, want length %v", got, tt.length)
+			}
+			for _, r := range got {
+				if !unicode.IsDigit(r) {
+					t.Errorf("GenerateNumericCode() = %v, contains non digit", got)
+				}
+			}
+		})
+	}
+}
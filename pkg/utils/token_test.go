@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestGenerateToken(t *testing.T) {
+	first, err := GenerateToken(32)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	second, err := GenerateToken(32)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	if first == second {
+		t.Errorf("GenerateToken() returned the same token twice: %v", first)
+	}
+	if len(first) != 43 {
+		t.Errorf("GenerateToken() = %v, want 43 characters", first)
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{
+			name:  "hash successfully",
+			token: "test",
+			want:  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HashToken(tt.token); got != tt.want {
+				t.Errorf("HashToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -19,11 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	AddressService_GetAddressByID_FullMethodName = "/address.AddressService/GetAddressByID"
-	AddressService_ListAddresses_FullMethodName  = "/address.AddressService/ListAddresses"
-	AddressService_CreateAddress_FullMethodName  = "/address.AddressService/CreateAddress"
-	AddressService_UpdateAddress_FullMethodName  = "/address.AddressService/UpdateAddress"
-	AddressService_DeleteAddress_FullMethodName  = "/address.AddressService/DeleteAddress"
+	AddressService_GetAddressByID_FullMethodName      = "/address.AddressService/GetAddressByID"
+	AddressService_ListAddresses_FullMethodName       = "/address.AddressService/ListAddresses"
+	AddressService_ListNearbyAddresses_FullMethodName = "/address.AddressService/ListNearbyAddresses"
+	AddressService_CreateAddress_FullMethodName       = "/address.AddressService/CreateAddress"
+	AddressService_UpdateAddress_FullMethodName       = "/address.AddressService/UpdateAddress"
+	AddressService_DeleteAddress_FullMethodName       = "/address.AddressService/DeleteAddress"
+	AddressService_GetDefaultAddress_FullMethodName   = "/address.AddressService/GetDefaultAddress"
+	AddressService_SetDefaultAddress_FullMethodName   = "/address.AddressService/SetDefaultAddress"
 )
 
 // AddressServiceClient is the client API for AddressService service.
@@ -32,9 +35,12 @@ const (
 type AddressServiceClient interface {
 	GetAddressByID(ctx context.Context, in *GetAddressByIDRequest, opts ...grpc.CallOption) (*AddressResponse, error)
 	ListAddresses(ctx context.Context, in *ListAddressesRequest, opts ...grpc.CallOption) (*ListAddressesResponse, error)
+	ListNearbyAddresses(ctx context.Context, in *ListNearbyAddressesRequest, opts ...grpc.CallOption) (*ListNearbyAddressesResponse, error)
 	CreateAddress(ctx context.Context, in *CreateAddressRequest, opts ...grpc.CallOption) (*AddressResponse, error)
 	UpdateAddress(ctx context.Context, in *UpdateAddressRequest, opts ...grpc.CallOption) (*AddressResponse, error)
 	DeleteAddress(ctx context.Context, in *DeleteAddressRequest, opts ...grpc.CallOption) (*AddressResponse, error)
+	GetDefaultAddress(ctx context.Context, in *GetDefaultAddressRequest, opts ...grpc.CallOption) (*AddressResponse, error)
+	SetDefaultAddress(ctx context.Context, in *SetDefaultAddressRequest, opts ...grpc.CallOption) (*AddressResponse, error)
 }
 
 type addressServiceClient struct {
@@ -63,6 +69,15 @@ func (c *addressServiceClient) ListAddresses(ctx context.Context, in *ListAddres
 	return out, nil
 }
 
+func (c *addressServiceClient) ListNearbyAddresses(ctx context.Context, in *ListNearbyAddressesRequest, opts ...grpc.CallOption) (*ListNearbyAddressesResponse, error) {
+	out := new(ListNearbyAddressesResponse)
+	err := c.cc.Invoke(ctx, AddressService_ListNearbyAddresses_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *addressServiceClient) CreateAddress(ctx context.Context, in *CreateAddressRequest, opts ...grpc.CallOption) (*AddressResponse, error) {
 	out := new(AddressResponse)
 	err := c.cc.Invoke(ctx, AddressService_CreateAddress_FullMethodName, in, out, opts...)
@@ -90,15 +105,36 @@ func (c *addressServiceClient) DeleteAddress(ctx context.Context, in *DeleteAddr
 	return out, nil
 }
 
+func (c *addressServiceClient) GetDefaultAddress(ctx context.Context, in *GetDefaultAddressRequest, opts ...grpc.CallOption) (*AddressResponse, error) {
+	out := new(AddressResponse)
+	err := c.cc.Invoke(ctx, AddressService_GetDefaultAddress_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *addressServiceClient) SetDefaultAddress(ctx context.Context, in *SetDefaultAddressRequest, opts ...grpc.CallOption) (*AddressResponse, error) {
+	out := new(AddressResponse)
+	err := c.cc.Invoke(ctx, AddressService_SetDefaultAddress_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AddressServiceServer is the server API for AddressService service.
 // All implementations must embed UnimplementedAddressServiceServer
 // for forward compatibility
 type AddressServiceServer interface {
 	GetAddressByID(context.Context, *GetAddressByIDRequest) (*AddressResponse, error)
 	ListAddresses(context.Context, *ListAddressesRequest) (*ListAddressesResponse, error)
+	ListNearbyAddresses(context.Context, *ListNearbyAddressesRequest) (*ListNearbyAddressesResponse, error)
 	CreateAddress(context.Context, *CreateAddressRequest) (*AddressResponse, error)
 	UpdateAddress(context.Context, *UpdateAddressRequest) (*AddressResponse, error)
 	DeleteAddress(context.Context, *DeleteAddressRequest) (*AddressResponse, error)
+	GetDefaultAddress(context.Context, *GetDefaultAddressRequest) (*AddressResponse, error)
+	SetDefaultAddress(context.Context, *SetDefaultAddressRequest) (*AddressResponse, error)
 	mustEmbedUnimplementedAddressServiceServer()
 }
 
@@ -112,6 +148,9 @@ func (UnimplementedAddressServiceServer) GetAddressByID(context.Context, *GetAdd
 func (UnimplementedAddressServiceServer) ListAddresses(context.Context, *ListAddressesRequest) (*ListAddressesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAddresses not implemented")
 }
+func (UnimplementedAddressServiceServer) ListNearbyAddresses(context.Context, *ListNearbyAddressesRequest) (*ListNearbyAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNearbyAddresses not implemented")
+}
 func (UnimplementedAddressServiceServer) CreateAddress(context.Context, *CreateAddressRequest) (*AddressResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateAddress not implemented")
 }
@@ -121,6 +160,12 @@ func (UnimplementedAddressServiceServer) UpdateAddress(context.Context, *UpdateA
 func (UnimplementedAddressServiceServer) DeleteAddress(context.Context, *DeleteAddressRequest) (*AddressResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteAddress not implemented")
 }
+func (UnimplementedAddressServiceServer) GetDefaultAddress(context.Context, *GetDefaultAddressRequest) (*AddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDefaultAddress not implemented")
+}
+func (UnimplementedAddressServiceServer) SetDefaultAddress(context.Context, *SetDefaultAddressRequest) (*AddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDefaultAddress not implemented")
+}
 func (UnimplementedAddressServiceServer) mustEmbedUnimplementedAddressServiceServer() {}
 
 // UnsafeAddressServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -170,6 +215,24 @@ func _AddressService_ListAddresses_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AddressService_ListNearbyAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNearbyAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddressServiceServer).ListNearbyAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AddressService_ListNearbyAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddressServiceServer).ListNearbyAddresses(ctx, req.(*ListNearbyAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AddressService_CreateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateAddressRequest)
 	if err := dec(in); err != nil {
@@ -224,6 +287,42 @@ func _AddressService_DeleteAddress_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AddressService_GetDefaultAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDefaultAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddressServiceServer).GetDefaultAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AddressService_GetDefaultAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddressServiceServer).GetDefaultAddress(ctx, req.(*GetDefaultAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AddressService_SetDefaultAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDefaultAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddressServiceServer).SetDefaultAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AddressService_SetDefaultAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddressServiceServer).SetDefaultAddress(ctx, req.(*SetDefaultAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AddressService_ServiceDesc is the grpc.ServiceDesc for AddressService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -239,6 +338,10 @@ var AddressService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListAddresses",
 			Handler:    _AddressService_ListAddresses_Handler,
 		},
+		{
+			MethodName: "ListNearbyAddresses",
+			Handler:    _AddressService_ListNearbyAddresses_Handler,
+		},
 		{
 			MethodName: "CreateAddress",
 			Handler:    _AddressService_CreateAddress_Handler,
@@ -251,6 +354,14 @@ var AddressService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteAddress",
 			Handler:    _AddressService_DeleteAddress_Handler,
 		},
+		{
+			MethodName: "GetDefaultAddress",
+			Handler:    _AddressService_GetDefaultAddress_Handler,
+		},
+		{
+			MethodName: "SetDefaultAddress",
+			Handler:    _AddressService_SetDefaultAddress_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/address/address.proto",
@@ -0,0 +1,359 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"main/internal/address/dto"
+	"main/internal/address/model"
+	addressRepository "main/internal/address/repository"
+)
+
+func TestAddressAPI_Unauthorized(t *testing.T) {
+	writer := makeRequest("GET", "/address", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("GET", "/address/some-id", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestAddressAPI_CreateIgnoresBodyOwner(t *testing.T) {
+	defer cleanData()
+
+	token, _ := sessionTokens("address-owner")
+	req := &dto.CreateAddressReq{IDUser: "someone-else", Name: "Home", City: "Cairo"}
+	writer := makeRequest("POST", "/address", req, token)
+	var res dto.Address
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "address-owner", res.IDUser)
+
+	cleanData(&model.Address{ID: res.ID})
+}
+
+func TestAddressAPI_ScopedToOwner(t *testing.T) {
+	mine := model.Address{IDUser: "address-owner", Name: "Home", City: "Cairo"}
+	theirs := model.Address{IDUser: "address-other", Name: "Work", City: "Giza"}
+	dbTest.Create(context.Background(), &mine)
+	dbTest.Create(context.Background(), &theirs)
+	defer cleanData(&mine, &theirs)
+
+	token, _ := sessionTokens("address-owner")
+
+	writer := makeRequest("GET", "/address", nil, token)
+	var list dto.ListAddressRes
+	parseResponseResult(writer.Body.Bytes(), &list)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, 1, len(list.Addresses))
+	assert.Equal(t, mine.ID, list.Addresses[0].ID)
+
+	writer = makeRequest("GET", "/address/"+mine.ID, nil, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("GET", "/address/"+theirs.ID, nil, token)
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+
+	writer = makeRequest("PUT", "/address/"+theirs.ID, &dto.UpdateAddressReq{Name: "Mine now"}, token)
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+
+	writer = makeRequest("DELETE", "/address/"+theirs.ID, nil, token)
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+
+	var found model.Address
+	assert.NoError(t, dbTest.FindById(context.Background(), theirs.ID, &found))
+	assert.Equal(t, "Work", found.Name)
+}
+
+func TestAddressAPI_AllIgnoredForCustomers(t *testing.T) {
+	theirs := model.Address{IDUser: "address-other", Name: "Work", City: "Giza"}
+	dbTest.Create(context.Background(), &theirs)
+	defer cleanData(&theirs)
+
+	token, _ := sessionTokens("address-owner")
+
+	writer := makeRequest("GET", "/address?all=true", nil, token)
+	var list dto.ListAddressRes
+	parseResponseResult(writer.Body.Bytes(), &list)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, 0, len(list.Addresses))
+
+	writer = makeRequest("DELETE", "/address/"+theirs.ID+"?all=true", nil, token)
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+}
+
+func TestAddressAPI_AdminBypass(t *testing.T) {
+	theirs := model.Address{IDUser: "address-other", Name: "Work", City: "Giza"}
+	dbTest.Create(context.Background(), &theirs)
+	defer cleanData(&theirs)
+
+	token := adminToken("address-admin")
+
+	// Admins are scoped like everyone else unless they ask for all users.
+	writer := makeRequest("GET", "/address/"+theirs.ID, nil, token)
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+
+	writer = makeRequest("GET", "/address?all=true&id_user=address-other", nil, token)
+	var list dto.ListAddressRes
+	parseResponseResult(writer.Body.Bytes(), &list)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, 1, len(list.Addresses))
+	assert.Equal(t, theirs.ID, list.Addresses[0].ID)
+
+	writer = makeRequest("PUT", "/address/"+theirs.ID+"?all=true", &dto.UpdateAddressReq{Name: "Office", City: "Giza"}, token)
+	var res dto.Address
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "Office", res.Name)
+	assert.Equal(t, "address-other", res.IDUser)
+
+	writer = makeRequest("DELETE", "/address/"+theirs.ID+"?all=true", nil, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestAddressAPI_FirstAddressIsDefault(t *testing.T) {
+	defer cleanData()
+
+	token, _ := sessionTokens("address-default")
+
+	writer := makeRequest("GET", "/address/default", nil, token)
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+
+	var first, second dto.Address
+	writer = makeRequest("POST", "/address", &dto.CreateAddressReq{Name: "Home", City: "Cairo"}, token)
+	parseResponseResult(writer.Body.Bytes(), &first)
+	assert.True(t, first.IsDefault)
+
+	writer = makeRequest("POST", "/address", &dto.CreateAddressReq{Name: "Work", City: "Giza"}, token)
+	parseResponseResult(writer.Body.Bytes(), &second)
+	assert.False(t, second.IsDefault)
+	defer cleanData(&model.Address{ID: first.ID}, &model.Address{ID: second.ID})
+
+	writer = makeRequest("GET", "/address/default", nil, token)
+	var res dto.Address
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, first.ID, res.ID)
+}
+
+func TestAddressAPI_SetDefault(t *testing.T) {
+	home := model.Address{IDUser: "address-default", Name: "Home", IsDefault: true}
+	work := model.Address{IDUser: "address-default", Name: "Work"}
+	dbTest.Create(context.Background(), &home)
+	dbTest.Create(context.Background(), &work)
+	defer cleanData(&home, &work)
+
+	token, _ := sessionTokens("address-default")
+
+	writer := makeRequest("PUT", "/address/"+work.ID+"/default", nil, token)
+	var res dto.Address
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.True(t, res.IsDefault)
+
+	var defaults int64
+	dbTest.GetDB().Model(&model.Address{}).Where("id_user = ? AND is_default", "address-default").Count(&defaults)
+	assert.Equal(t, int64(1), defaults)
+
+	writer = makeRequest("GET", "/address/default", nil, token)
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, work.ID, res.ID)
+
+	other, _ := sessionTokens("address-other")
+	writer = makeRequest("PUT", "/address/"+home.ID+"/default", nil, other)
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+}
+
+func TestAddressAPI_DeleteDefaultPromotesAnother(t *testing.T) {
+	home := model.Address{IDUser: "address-default", Name: "Home", IsDefault: true}
+	work := model.Address{IDUser: "address-default", Name: "Work"}
+	dbTest.Create(context.Background(), &home)
+	dbTest.Create(context.Background(), &work)
+	defer cleanData(&home, &work)
+
+	token, _ := sessionTokens("address-default")
+
+	writer := makeRequest("DELETE", "/address/"+home.ID, nil, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("GET", "/address/default", nil, token)
+	var res dto.Address
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, work.ID, res.ID)
+}
+
+func coordinate(f float64) *float64 {
+	return &f
+}
+
+func TestAddressAPI_CreateInvalidCoordinates(t *testing.T) {
+	token, _ := sessionTokens("address-owner")
+
+	tests := []*dto.CreateAddressReq{
+		{Name: "Pole", Lat: coordinate(90.5), Long: coordinate(0)},
+		{Name: "Dateline", Lat: coordinate(0), Long: coordinate(-180.5)},
+		{Name: "Half", Lat: coordinate(30)},
+	}
+	for _, req := range tests {
+		writer := makeRequest("POST", "/address", req, token)
+		assert.Equal(t, http.StatusBadRequest, writer.Code, req.Name)
+	}
+}
+
+func TestAddressAPI_ListNearby(t *testing.T) {
+	// Tahrir Square, Giza Pyramids (~13 km away), Alexandria (~180 km away)
+	// and an address without coordinates.
+	tahrir := model.Address{IDUser: "address-nearby", Name: "Tahrir", Lat: coordinate(30.0444), Long: coordinate(31.2357)}
+	giza := model.Address{IDUser: "address-nearby", Name: "Giza", Lat: coordinate(29.9792), Long: coordinate(31.1342)}
+	alexandria := model.Address{IDUser: "address-nearby", Name: "Alexandria", Lat: coordinate(31.2001), Long: coordinate(29.9187)}
+	unknown := model.Address{IDUser: "address-nearby", Name: "Unknown"}
+	theirs := model.Address{IDUser: "address-other", Name: "Theirs", Lat: coordinate(30.0444), Long: coordinate(31.2357)}
+	for _, address := range []*model.Address{&tahrir, &giza, &alexandria, &unknown, &theirs} {
+		dbTest.Create(context.Background(), address)
+	}
+	defer cleanData(&tahrir, &giza, &alexandria, &unknown, &theirs)
+
+	token, _ := sessionTokens("address-nearby")
+
+	writer := makeRequest("GET", "/address/nearby?lat=30.05&long=31.24&radius_km=20", nil, token)
+	var res dto.ListNearbyAddressRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, 2, len(res.Addresses))
+	assert.Equal(t, tahrir.ID, res.Addresses[0].ID)
+	assert.Equal(t, giza.ID, res.Addresses[1].ID)
+	assert.Less(t, res.Addresses[0].DistanceKm, 1.0)
+	assert.InDelta(t, 13, res.Addresses[1].DistanceKm, 2)
+
+	writer = makeRequest("GET", "/address/nearby?lat=30.05&long=31.24&radius_km=500", nil, token)
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, 3, len(res.Addresses))
+	assert.Equal(t, alexandria.ID, res.Addresses[2].ID)
+
+	writer = makeRequest("GET", "/address/nearby?lat=95&long=31.24&radius_km=20", nil, token)
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+
+	writer = makeRequest("GET", "/address/nearby?long=31.24&radius_km=20", nil, token)
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+}
+
+// legacyAddress is the addresses table from before the coordinates were
+// numbers.
+type legacyAddress struct {
+	ID     string
+	IDUser string
+	Name   string
+	Lat    string
+	Long   string
+}
+
+func (legacyAddress) TableName() string {
+	return "addresses"
+}
+
+func TestAddressAPI_MigrateCoordinates(t *testing.T) {
+	migrator := dbTest.GetDB().Migrator()
+	assert.NoError(t, migrator.DropTable(&model.Address{}))
+	assert.NoError(t, migrator.CreateTable(&legacyAddress{}))
+	defer func() {
+		_ = migrator.DropTable(&model.Address{})
+		_ = dbTest.AutoMigrate(&model.Address{})
+	}()
+
+	rows := []legacyAddress{
+		{ID: "valid", IDUser: "address-legacy", Lat: " 30.0444", Long: "31.2357 "},
+		{ID: "empty", IDUser: "address-legacy"},
+		{ID: "garbage", IDUser: "address-legacy", Lat: "north", Long: "east"},
+		{ID: "range", IDUser: "address-legacy", Lat: "120", Long: "31"},
+	}
+	for _, row := range rows {
+		assert.NoError(t, dbTest.GetDB().Create(&row).Error)
+	}
+
+	failures, err := addressRepository.MigrateCoordinates(context.Background(), dbTest)
+	assert.NoError(t, err)
+	assert.NoError(t, dbTest.AutoMigrate(&model.Address{}))
+
+	var failed []string
+	for _, failure := range failures {
+		failed = append(failed, failure.ID)
+	}
+	assert.ElementsMatch(t, []string{"garbage", "range"}, failed)
+
+	var valid, garbage model.Address
+	assert.NoError(t, dbTest.FindById(context.Background(), "valid", &valid))
+	assert.Equal(t, 30.0444, *valid.Lat)
+	assert.Equal(t, 31.2357, *valid.Long)
+	assert.NoError(t, dbTest.FindById(context.Background(), "garbage", &garbage))
+	assert.Nil(t, garbage.Lat)
+	assert.Nil(t, garbage.Long)
+
+	// Numeric columns are left alone.
+	failures, err = addressRepository.MigrateCoordinates(context.Background(), dbTest)
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+}
+
+func TestAddressAPI_CreateGeocodes(t *testing.T) {
+	defer cleanData()
+
+	token, _ := sessionTokens("address-geocode")
+
+	writer := makeRequest("POST", "/address", &dto.CreateAddressReq{Name: "Home", City: "London", Street: "Baker Street"}, token)
+	var res dto.Address
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	if assert.NotNil(t, res.Lat) && assert.NotNil(t, res.Long) {
+		assert.InDelta(t, 51.5237, *res.Lat, 0.0001)
+		assert.InDelta(t, -0.1585, *res.Long, 0.0001)
+	}
+	cleanData(&model.Address{ID: res.ID})
+
+	// Sent coordinates are kept.
+	req := &dto.CreateAddressReq{Name: "Home", City: "London", Street: "Baker Street", Lat: coordinate(51.5), Long: coordinate(-0.1)}
+	writer = makeRequest("POST", "/address", req, token)
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, 51.5, *res.Lat)
+	cleanData(&model.Address{ID: res.ID})
+
+	// Unknown places are saved as sent.
+	writer = makeRequest("POST", "/address", &dto.CreateAddressReq{Name: "Home", City: "Atlantis"}, token)
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Nil(t, res.Lat)
+	cleanData(&model.Address{ID: res.ID})
+}
+
+func TestAddressAPI_CreateReverseGeocodes(t *testing.T) {
+	defer cleanData()
+
+	token, _ := sessionTokens("address-geocode")
+
+	req := &dto.CreateAddressReq{Name: "Pin", Lat: coordinate(30.0445), Long: coordinate(31.2359)}
+	writer := makeRequest("POST", "/address", req, token)
+	var res dto.Address
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "Cairo", res.City)
+	assert.Equal(t, "Tahrir Square", res.Street)
+	cleanData(&model.Address{ID: res.ID})
+}
+
+func TestAddressAPI_UpdateGeocodes(t *testing.T) {
+	home := model.Address{IDUser: "address-geocode", Name: "Home", City: "Cairo"}
+	dbTest.Create(context.Background(), &home)
+	defer cleanData(&home)
+
+	token, _ := sessionTokens("address-geocode")
+
+	writer := makeRequest("PUT", "/address/"+home.ID, &dto.UpdateAddressReq{Name: "Home", City: "Paris"}, token)
+	var res dto.Address
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	if assert.NotNil(t, res.Lat) {
+		assert.InDelta(t, 48.8566, *res.Lat, 0.0001)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/quangdangfit/gocommon/logger"
@@ -15,11 +17,18 @@ import (
 
 	// orderModel "main/internal/order/model"
 	// productModel "main/internal/product/model"
+	addressModel "main/internal/address/model"
+	addressRepository "main/internal/address/repository"
 	httpServer "main/internal/server/http"
 	"main/internal/user/dto"
 	userModel "main/internal/user/model"
+	"main/pkg/apikey"
+	"main/pkg/audit"
 	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/jtoken"
+	"main/pkg/middleware"
+	"main/pkg/oidc/oidctest"
 	"main/pkg/redis"
 	"main/pkg/utils"
 )
@@ -29,6 +38,7 @@ var (
 	testRouter *gin.Engine
 	dbTest     dbs.IDatabase
 	testCache  redis.IRedis
+	testIdP    *oidctest.Server
 )
 
 // TestMain is the entry point for the test suite. It sets the Gin mode to test,
@@ -60,7 +70,10 @@ func setup() {
 	}
 
 	// Perform database migration
-	err = dbTest.AutoMigrate(&userModel.User{})
+	if _, err = addressRepository.MigrateCoordinates(context.Background(), dbTest); err != nil {
+		logger.Fatal("Address coordinates migration fail", err)
+	}
+	err = dbTest.AutoMigrate(&userModel.User{}, &addressModel.Address{}, &apikey.Key{}, &audit.Event{})
 	if err != nil {
 		logger.Fatal("Database migration fail", err)
 	}
@@ -75,6 +88,16 @@ func setup() {
 		Database: cfg.RedisDB,
 	})
 
+	// Start the OpenID Connect provider the users log in with
+	testIdP = oidctest.NewServer("test-client", "test-secret")
+	cfg.OIDCIssuer = testIdP.Issuer()
+	cfg.OIDCClientID = testIdP.ClientID
+	cfg.OIDCClientSecret = testIdP.ClientSecret
+	cfg.OIDCRedirectURL = "http://localhost/auth/oidc/callback"
+
+	// Geocode addresses with the shipped gazetteer
+	cfg.GeocoderGazetteerFile = "../../data/gazetteer.csv"
+
 	// Initialize the HTTP server
 	server := httpServer.NewServer(validator, dbTest, testCache)
 	_ = server.MapRoutes()
@@ -90,8 +113,9 @@ func setup() {
 // teardown cleans up the test environment, including dropping the test database
 // table.
 func teardown() {
+	testIdP.Close()
 	migrator := dbTest.GetDB().Migrator()
-	migrator.DropTable(&userModel.User{})
+	migrator.DropTable(&userModel.User{}, &addressModel.Address{}, &apikey.Key{}, &audit.Event{})
 }
 
 // makeRequest creates and sends an HTTP request to the test router, and returns
@@ -107,6 +131,38 @@ func makeRequest(method, url string, body interface{}, token string) *httptest.R
 	return writer
 }
 
+// makeKeyRequest sends an HTTP request authenticated with an API key to the
+// test router, and returns the response.
+func makeKeyRequest(method, url string, body interface{}, key string) *httptest.ResponseRecorder {
+	requestBody, _ := json.Marshal(body)
+	request, _ := http.NewRequest(method, url, bytes.NewBuffer(requestBody))
+	request.Header.Add(middleware.APIKeyHeader, key)
+	writer := httptest.NewRecorder()
+	testRouter.ServeHTTP(writer, request)
+	return writer
+}
+
+// oidcCallback starts an OpenID Connect login, lets the test provider log in
+// its current user and returns the callback url it redirects back to.
+func oidcCallback() string {
+	writer := makeRequest("GET", "/auth/oidc/login", nil, "")
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Get(writer.Header().Get("Location"))
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return ""
+	}
+	return callback.RequestURI()
+}
+
 // accessToken returns the access token for the test user.
 func accessToken() string {
 	user := dto.LoginReq{
@@ -133,6 +189,47 @@ func refreshToken() string {
 	return response["result"]["refresh_token"]
 }
 
+// sessionTokens opens a session for the given user id directly in the token
+// store and returns an access/refresh token pair bound to it.
+func sessionTokens(userID string) (string, string) {
+	session := &jtoken.Session{
+		ID:        "session-" + userID,
+		UserID:    userID,
+		TokenID:   "token-" + userID,
+		CreatedAt: time.Now(),
+	}
+	_ = jtoken.NewStore(testCache).Save(session)
+
+	access := jtoken.GenerateAccessToken(jtoken.Claims{
+		Subject:   userID,
+		SessionID: session.ID,
+	})
+	refresh := jtoken.GenerateRefreshToken(jtoken.Claims{
+		Subject:   userID,
+		SessionID: session.ID,
+		ID:        session.TokenID,
+	})
+	return access, refresh
+}
+
+// adminToken opens a session for the given user id and returns an access
+// token carrying the admin role.
+func adminToken(userID string) string {
+	session := &jtoken.Session{
+		ID:        "session-" + userID,
+		UserID:    userID,
+		TokenID:   "token-" + userID,
+		CreatedAt: time.Now(),
+	}
+	_ = jtoken.NewStore(testCache).Save(session)
+
+	return jtoken.GenerateAccessToken(jtoken.Claims{
+		Subject:   userID,
+		Role:      string(userModel.UserRoleAdmin),
+		SessionID: session.ID,
+	})
+}
+
 // parseResponseResult parses the "result" field from the given response data
 // and copies it to the given result object.
 func parseResponseResult(resData []byte, result interface{}) {
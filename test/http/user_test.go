@@ -1,16 +1,34 @@
 package http
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 
+	addressModel "main/internal/address/model"
 	"main/internal/user/dto"
 	"main/internal/user/model"
+	"main/internal/user/repository"
+	"main/pkg/apikey"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/dbs"
 	"main/pkg/jtoken"
+	"main/pkg/middleware"
+	"main/pkg/oidc/oidctest"
+	"main/pkg/password"
+	"main/pkg/totp"
+	"main/pkg/utils"
 )
 
 // Login
@@ -90,6 +108,146 @@ func TestUserAPI_LoginUserWrongPassword(t *testing.T) {
 	assert.Equal(t, "Something went wrong", response["error"]["message"])
 }
 
+func TestUserAPI_LoginLockout(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "lockout@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+
+	wrong := &dto.LoginReq{Email: user.Email, Password: "wrong123456"}
+	writer := makeRequest("POST", "/auth/login", wrong, "")
+	assert.Equal(t, http.StatusInternalServerError, writer.Code)
+	writer = makeRequest("POST", "/auth/login", wrong, "")
+	assert.Equal(t, http.StatusInternalServerError, writer.Code)
+
+	login := &dto.LoginReq{Email: user.Email, Password: "test123456"}
+	writer = makeRequest("POST", "/auth/login", login, "")
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+	assert.NotEmpty(t, writer.Header().Get("Retry-After"))
+
+	writer = makeRequest("PUT", "/admin/users/"+user.ID+"/unlock?ip=192.0.2.1", nil, adminToken("admin-unlock"))
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("POST", "/auth/login", login, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestUserAPI_LoginRehashesLegacyPassword(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "legacyhash@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("test123456"), bcrypt.MinCost)
+	_ = dbTest.Exec(context.Background(), "UPDATE users SET password = ? WHERE id = ?", string(legacy), user.ID)
+
+	login := &dto.LoginReq{Email: user.Email, Password: "test123456"}
+	writer := makeRequest("POST", "/auth/login", login, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	_ = dbTest.FindById(context.Background(), user.ID, &user)
+	assert.NotEqual(t, string(legacy), user.Password)
+	assert.False(t, password.NeedsRehash(user.Password))
+	assert.NoError(t, password.Compare(user.Password, "test123456"))
+
+	writer = makeRequest("POST", "/auth/login", login, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+// Phone OTP
+// =================================================================================================
+
+// setOTP replaces the code sent to phone, which only goes out by SMS, with a
+// known one.
+func setOTP(phone, code string) {
+	_ = testCache.SetWithExpiration("otp:code:"+phone, map[string]interface{}{
+		"hash":       utils.HashToken(phone + ":" + code),
+		"expires_at": time.Now().Add(time.Minute),
+	}, time.Minute)
+}
+
+func TestUserAPI_OTPLoginSuccess(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "otp@test.com", Password: "test123456", Phone: "+14155550111"}
+	dbTest.Create(context.Background(), &user)
+
+	writer := makeRequest("POST", "/auth/otp/request", &dto.RequestOTPReq{Phone: user.Phone}, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+	setOTP(user.Phone, "123456")
+
+	req := &dto.VerifyOTPReq{Phone: user.Phone, Code: "123456"}
+	writer = makeRequest("POST", "/auth/otp/verify", req, "")
+	var res dto.LoginRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotEmpty(t, res.AccessToken)
+	assert.NotEmpty(t, res.RefreshToken)
+	assert.True(t, res.User.PhoneVerified)
+
+	writer = makeRequest("POST", "/auth/otp/verify", req, "")
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestUserAPI_OTPWrongCode(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "otpwrong@test.com", Password: "test123456", Phone: "+14155550112"}
+	dbTest.Create(context.Background(), &user)
+	setOTP(user.Phone, "123456")
+
+	req := &dto.VerifyOTPReq{Phone: user.Phone, Code: "654321"}
+	writer := makeRequest("POST", "/auth/otp/verify", req, "")
+	var response map[string]map[string]string
+	_ = json.Unmarshal(writer.Body.Bytes(), &response)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+	assert.Equal(t, "Invalid or expired code", response["error"]["message"])
+}
+
+func TestUserAPI_OTPRequestRateLimited(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "otplimit@test.com", Password: "test123456", Phone: "+14155550113"}
+	dbTest.Create(context.Background(), &user)
+
+	req := &dto.RequestOTPReq{Phone: user.Phone}
+	writer := makeRequest("POST", "/auth/otp/request", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+	writer = makeRequest("POST", "/auth/otp/request", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("POST", "/auth/otp/request", req, "")
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+	assert.NotEmpty(t, writer.Header().Get("Retry-After"))
+}
+
+func TestUserAPI_OTPRequestUnknownPhone(t *testing.T) {
+	defer cleanData()
+
+	// Limited like a registered phone, see TestUserAPI_OTPRequestRateLimited.
+	req := &dto.RequestOTPReq{Phone: "+14155550199"}
+	writer := makeRequest("POST", "/auth/otp/request", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+	writer = makeRequest("POST", "/auth/otp/request", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("POST", "/auth/otp/request", req, "")
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+	assert.NotEmpty(t, writer.Header().Get("Retry-After"))
+}
+
+func TestUserAPI_UpdateProfilePhoneTaken(t *testing.T) {
+	defer cleanData()
+
+	dbTest.Create(context.Background(), &model.User{Email: "phoneowner@test.com", Password: "test123456", Phone: "+14155550114"})
+	user := model.User{Email: "phonetaker@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	token, _ := sessionTokens(user.ID)
+
+	writer := makeRequest("PATCH", "/auth/me", map[string]interface{}{"phone": "+14155550114"}, token)
+	assert.Equal(t, http.StatusConflict, writer.Code)
+}
+
 // Register
 // =================================================================================================
 
@@ -159,6 +317,115 @@ func TestUserAPI_RegisterEmailExist(t *testing.T) {
 	assert.Equal(t, "Something went wrong", response["error"]["message"])
 }
 
+// Verify Code
+// =================================================================================================
+
+func TestUserAPI_VerifyCodeSuccess(t *testing.T) {
+	defer cleanData()
+
+	req := &dto.RegisterReq{
+		Email:    "verify@test.com",
+		Password: "test123456",
+	}
+	writer := makeRequest("POST", "/auth/register", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	var user model.User
+	_ = dbTest.FindOne(context.Background(), &user, dbs.WithQuery(dbs.NewQuery("email = ?", req.Email)))
+	assert.Len(t, user.VerifyCode, len(utils.HashToken("123456")))
+	assert.False(t, user.Approve)
+
+	// Only the hash of the mailed code is stored, so the test sets its own.
+	_ = dbTest.Exec(context.Background(), "UPDATE users SET verify_code = ? WHERE id = ?", utils.HashToken("123456"), user.ID)
+	writer = makeRequest("PUT", "/auth/verify-code", &dto.VerifyRequest{Email: req.Email, VerifyCode: "123456"}, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	_ = dbTest.FindOne(context.Background(), &user, dbs.WithQuery(dbs.NewQuery("email = ?", req.Email)))
+	assert.True(t, user.Approve)
+	assert.Empty(t, user.VerifyCode)
+}
+
+func TestUserAPI_VerifyCodeWrongCode(t *testing.T) {
+	defer cleanData()
+
+	req := &dto.RegisterReq{
+		Email:    "verifywrong@test.com",
+		Password: "test123456",
+	}
+	writer := makeRequest("POST", "/auth/register", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("PUT", "/auth/verify-code", &dto.VerifyRequest{Email: req.Email, VerifyCode: "wrong"}, "")
+	var response map[string]map[string]string
+	_ = json.Unmarshal(writer.Body.Bytes(), &response)
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+	assert.Equal(t, "Verify code not correct", response["error"]["message"])
+
+	var user model.User
+	_ = dbTest.FindOne(context.Background(), &user, dbs.WithQuery(dbs.NewQuery("email = ?", req.Email)))
+	assert.Equal(t, 1, user.VerifyAttempts)
+}
+
+func TestUserAPI_ResendVerifyCodeSuccess(t *testing.T) {
+	defer cleanData()
+
+	req := &dto.RegisterReq{
+		Email:    "resend@test.com",
+		Password: "test123456",
+	}
+	writer := makeRequest("POST", "/auth/register", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("POST", "/auth/resend-verify-code", &dto.ResendVerifyCodeReq{Email: req.Email}, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestUserAPI_ResendVerifyCodeResetsAttempts(t *testing.T) {
+	defer cleanData()
+
+	req := &dto.RegisterReq{
+		Email:    "resendattempts@test.com",
+		Password: "test123456",
+	}
+	writer := makeRequest("POST", "/auth/register", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("PUT", "/auth/verify-code", &dto.VerifyRequest{Email: req.Email, VerifyCode: "wrong"}, "")
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+
+	writer = makeRequest("POST", "/auth/resend-verify-code", &dto.ResendVerifyCodeReq{Email: req.Email}, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	var user model.User
+	_ = dbTest.FindOne(context.Background(), &user, dbs.WithQuery(dbs.NewQuery("email = ?", req.Email)))
+	assert.Equal(t, 0, user.VerifyAttempts)
+}
+
+func TestUserAPI_ResendVerifyCodeAlreadyVerified(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "resendverified@test.com", Password: "test123456", Approve: true}
+	dbTest.Create(context.Background(), &user)
+
+	// Answered like an unknown email, so that accounts cannot be discovered.
+	writer := makeRequest("POST", "/auth/resend-verify-code", &dto.ResendVerifyCodeReq{Email: user.Email}, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestUserAPI_ResendVerifyCodeRateLimited(t *testing.T) {
+	defer cleanData()
+
+	req := &dto.ResendVerifyCodeReq{Email: "resendunknown@test.com"}
+	writer := makeRequest("POST", "/auth/resend-verify-code", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+	writer = makeRequest("POST", "/auth/resend-verify-code", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("POST", "/auth/resend-verify-code", req, "")
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+	assert.NotEmpty(t, writer.Header().Get("Retry-After"))
+}
+
 // GetMe
 // =================================================================================================
 
@@ -171,15 +438,53 @@ func TestUserAPI_GetMeSuccess(t *testing.T) {
 	assert.Equal(t, "", response["result"]["password"])
 }
 
+func TestUserAPI_UpdateProfileSuccess(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "profile@test.com", Password: "test123456", Phone: "+14155550100"}
+	dbTest.Create(context.Background(), &user)
+	token, _ := sessionTokens(user.ID)
+
+	req := map[string]interface{}{
+		"display_name": " Jane Doe ",
+		"avatar_url":   "https://example.com/avatar.png",
+		"locale":       "en-US",
+	}
+	writer := makeRequest("PATCH", "/auth/me", req, token)
+	var res dto.User
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "Jane Doe", res.DisplayName)
+	assert.Equal(t, "+14155550100", res.Phone)
+	assert.Equal(t, "en-US", res.Locale)
+	assert.Equal(t, "customer", res.Role)
+
+	writer = makeRequest("PATCH", "/auth/me", map[string]interface{}{"phone": ""}, token)
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Empty(t, res.Phone)
+	assert.Equal(t, "Jane Doe", res.DisplayName)
+}
+
+func TestUserAPI_UpdateProfileInvalidFields(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"phone": "12345"},
+		{"avatar_url": "javascript:alert(1)"},
+		{"locale": "not a locale"},
+	}
+	for _, req := range tests {
+		writer := makeRequest("PATCH", "/auth/me", req, accessToken())
+		assert.Equal(t, http.StatusInternalServerError, writer.Code, req)
+	}
+}
+
 func TestUserAPI_GetMeUnauthorized(t *testing.T) {
 	writer := makeRequest("GET", "/auth/me", nil, "")
 	assert.Equal(t, http.StatusUnauthorized, writer.Code)
 }
 
 func TestUserAPI_GetMeUserNotFound(t *testing.T) {
-	token := jtoken.GenerateAccessToken(map[string]interface{}{
-		"id": "user-not-found",
-	})
+	token, _ := sessionTokens("user-not-found")
 
 	writer := makeRequest("GET", "/auth/me", nil, token)
 	var response map[string]map[string]string
@@ -188,6 +493,16 @@ func TestUserAPI_GetMeUserNotFound(t *testing.T) {
 	assert.Equal(t, "Something went wrong", response["error"]["message"])
 }
 
+func TestUserAPI_GetMeRevokedSession(t *testing.T) {
+	token := jtoken.GenerateAccessToken(jtoken.Claims{
+		Subject:   "user-without-session",
+		SessionID: "session-not-found",
+	})
+
+	writer := makeRequest("GET", "/auth/me", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
 func TestUserAPI_GetMeInvalidTokenType(t *testing.T) {
 	writer := makeRequest("GET", "/auth/me", nil, refreshToken())
 	var response map[string]map[string]string
@@ -195,15 +510,160 @@ func TestUserAPI_GetMeInvalidTokenType(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, writer.Code)
 }
 
+// Export and Delete Account
+// =================================================================================================
+
+func TestUserAPI_ExportAccountJSON(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "export@test.com", Password: "test123456", DisplayName: "Export"}
+	dbTest.Create(context.Background(), &user)
+	dbTest.Create(context.Background(), &addressModel.Address{IDUser: user.ID, Name: "Home", City: "Cairo"})
+	token, _ := sessionTokens(user.ID)
+
+	writer := makeRequest("GET", "/auth/me/export", nil, token)
+	var res dto.AccountExport
+	_ = json.Unmarshal(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Contains(t, writer.Header().Get("Content-Disposition"), "account.json")
+	assert.Equal(t, user.Email, res.User.Email)
+	assert.Equal(t, "Export", res.User.DisplayName)
+	assert.Equal(t, 1, len(res.Addresses))
+	assert.Equal(t, "Cairo", res.Addresses[0].City)
+	assert.Equal(t, 1, len(res.Sessions))
+}
+
+func TestUserAPI_ExportAccountZIP(t *testing.T) {
+	writer := makeRequest("GET", "/auth/me/export?format=zip", nil, accessToken())
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "application/zip", writer.Header().Get("Content-Type"))
+
+	archive, err := zip.NewReader(bytes.NewReader(writer.Body.Bytes()), int64(writer.Body.Len()))
+	assert.NoError(t, err)
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.ElementsMatch(t, []string{"user.json", "addresses.json", "sessions.json"}, names)
+}
+
+func TestUserAPI_DeleteAccount(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "deleteaccount@test.com", Password: "test123456", Phone: "+201000000099"}
+	dbTest.Create(context.Background(), &user)
+	address := addressModel.Address{IDUser: user.ID, Name: "Home"}
+	dbTest.Create(context.Background(), &address)
+	token, refresh := sessionTokens(user.ID)
+
+	writer := makeRequest("DELETE", "/auth/me", &dto.DeleteAccountReq{Password: "wrong123456"}, token)
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+
+	writer = makeRequest("DELETE", "/auth/me", &dto.DeleteAccountReq{Password: "test123456"}, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("POST", "/auth/refresh", nil, refresh)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	login := dto.LoginReq{Email: user.Email, Password: "test123456"}
+	writer = makeRequest("POST", "/auth/login", login, "")
+	assert.NotEqual(t, http.StatusOK, writer.Code)
+
+	var found model.User
+	assert.Error(t, dbTest.FindById(context.Background(), user.ID, &found))
+	assert.NoError(t, dbTest.GetDB().Unscoped().First(&found, "id = ?", user.ID).Error)
+	assert.True(t, found.DeletedAt.Valid)
+
+	var foundAddress addressModel.Address
+	assert.Error(t, dbTest.FindById(context.Background(), address.ID, &foundAddress))
+
+	exists, err := repository.NewUserRepository(dbTest).PhoneExists(context.Background(), "+201000000099")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	register := &dto.RegisterReq{Email: "deleteaccount@test.com", Password: "test123456"}
+	writer = makeRequest("POST", "/auth/register", register, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	purged, err := repository.NewUserRepository(dbTest).PurgeDeleted(context.Background(), time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Error(t, dbTest.GetDB().Unscoped().First(&found, "id = ?", user.ID).Error)
+	assert.Error(t, dbTest.GetDB().Unscoped().First(&foundAddress, "id = ?", address.ID).Error)
+}
+
 // Refresh Token
 // =================================================================================================
 
 func TestUserAPI_RefreshTokenSuccess(t *testing.T) {
-	writer := makeRequest("POST", "/auth/refresh", nil, refreshToken())
+	token := refreshToken()
+	writer := makeRequest("POST", "/auth/refresh", nil, token)
+	var response map[string]map[string]string
+	_ = json.Unmarshal(writer.Body.Bytes(), &response)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotEmpty(t, response["result"]["access_token"])
+	assert.NotEmpty(t, response["result"]["refresh_token"])
+	assert.NotEqual(t, token, response["result"]["refresh_token"])
+}
+
+func TestUserAPI_RefreshTokenReuseRevokesSession(t *testing.T) {
+	token := refreshToken()
+	writer := makeRequest("POST", "/auth/refresh", nil, token)
 	var response map[string]map[string]string
 	_ = json.Unmarshal(writer.Body.Bytes(), &response)
 	assert.Equal(t, http.StatusOK, writer.Code)
-	assert.NotNil(t, response["result"]["access_token"])
+	rotated := response["result"]["refresh_token"]
+
+	writer = makeRequest("POST", "/auth/refresh", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("POST", "/auth/refresh", nil, rotated)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestUserAPI_RefreshTokenConcurrentReuse(t *testing.T) {
+	token := refreshToken()
+
+	var wg sync.WaitGroup
+	codes := make([]int, 2)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = makeRequest("POST", "/auth/refresh", nil, token).Code
+		}(i)
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, []int{http.StatusOK, http.StatusUnauthorized}, codes)
+}
+
+func TestUserAPI_LoginConcurrentGuesses(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "parallel@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+
+	wrong := &dto.LoginReq{Email: user.Email, Password: "wrong123456"}
+	var wg sync.WaitGroup
+	codes := make([]int, 10)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = makeRequest("POST", "/auth/login", wrong, "").Code
+		}(i)
+	}
+	wg.Wait()
+
+	// The second guess already starts a delay, so the rest are refused.
+	tried := 0
+	for _, code := range codes {
+		if code != http.StatusTooManyRequests {
+			tried++
+		}
+	}
+	assert.Equal(t, 2, tried)
 }
 
 func TestUserAPI_RefreshTokenUnauthorized(t *testing.T) {
@@ -221,9 +681,7 @@ func TestUserAPI_RefreshTokenInvalidTokenType(t *testing.T) {
 }
 
 func TestUserAPI_RefreshTokenUserNotFound(t *testing.T) {
-	token := jtoken.GenerateRefreshToken(map[string]interface{}{
-		"id": "user-not-found",
-	})
+	_, token := sessionTokens("user-not-found")
 
 	writer := makeRequest("POST", "/auth/refresh", nil, token)
 	var response map[string]map[string]string
@@ -232,6 +690,35 @@ func TestUserAPI_RefreshTokenUserNotFound(t *testing.T) {
 	assert.Equal(t, "Something went wrong", response["error"]["message"])
 }
 
+// Logout
+// =================================================================================================
+
+func TestUserAPI_LogoutSuccess(t *testing.T) {
+	user := dto.LoginReq{
+		Email:    "test@test.com",
+		Password: "test123456",
+	}
+	writer := makeRequest("POST", "/auth/login", user, "")
+	var response map[string]map[string]string
+	_ = json.Unmarshal(writer.Body.Bytes(), &response)
+	access := response["result"]["access_token"]
+	refresh := response["result"]["refresh_token"]
+
+	writer = makeRequest("POST", "/auth/logout", nil, access)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("GET", "/auth/me", nil, access)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("POST", "/auth/refresh", nil, refresh)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestUserAPI_LogoutUnauthorized(t *testing.T) {
+	writer := makeRequest("POST", "/auth/logout", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
 // Change Password
 // =================================================================================================
 
@@ -241,9 +728,7 @@ func TestUserAPI_ChangePasswordSuccess(t *testing.T) {
 	user := model.User{Email: "changepassword1@gmail.com", Password: "123456"}
 	dbTest.Create(context.Background(), &user)
 
-	token := jtoken.GenerateAccessToken(map[string]interface{}{
-		"id": user.ID,
-	})
+	token, _ := sessionTokens(user.ID)
 
 	req := &dto.ChangePasswordReq{
 		Password:    "123456",
@@ -302,3 +787,806 @@ func TestUserAPI_ChangePasswordInvalidFieldType(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, writer.Code)
 	assert.Equal(t, "Invalid parameters", response["error"]["message"])
 }
+
+// Change Email
+// =================================================================================================
+
+func TestUserAPI_ChangeEmailSuccess(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "changeemail@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	token, refresh := sessionTokens(user.ID)
+
+	req := &dto.ChangeEmailReq{Email: "changedemail@test.com", Password: "test123456"}
+	writer := makeRequest("POST", "/auth/change-email", req, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	_ = dbTest.FindById(context.Background(), user.ID, &user)
+	assert.Equal(t, "changeemail@test.com", user.Email)
+	assert.Equal(t, "changedemail@test.com", user.PendingEmail)
+
+	// The code is only mailed, so replace it with a known one.
+	_ = dbTest.Exec(context.Background(), "UPDATE users SET email_change_code_hash = ? WHERE id = ?", utils.HashToken("123456"), user.ID)
+
+	writer = makeRequest("POST", "/auth/change-email/confirm", &dto.ConfirmEmailChangeReq{Code: "000000"}, token)
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+
+	writer = makeRequest("POST", "/auth/change-email/confirm", &dto.ConfirmEmailChangeReq{Code: "123456"}, token)
+	var res dto.User
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "changedemail@test.com", res.Email)
+
+	writer = makeRequest("GET", "/auth/me", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("POST", "/auth/refresh", nil, refresh)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	login := dto.LoginReq{Email: "changedemail@test.com", Password: "test123456"}
+	writer = makeRequest("POST", "/auth/login", login, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestUserAPI_ChangeEmailTakenBeforeConfirm(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "changeemailrace@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	token, _ := sessionTokens(user.ID)
+
+	req := &dto.ChangeEmailReq{Email: "takenlater@test.com", Password: "test123456"}
+	writer := makeRequest("POST", "/auth/change-email", req, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	dbTest.Create(context.Background(), &model.User{Email: "takenlater@test.com", Password: "test123456"})
+	_ = dbTest.Exec(context.Background(), "UPDATE users SET email_change_code_hash = ? WHERE id = ?", utils.HashToken("123456"), user.ID)
+
+	writer = makeRequest("POST", "/auth/change-email/confirm", &dto.ConfirmEmailChangeReq{Code: "123456"}, token)
+	assert.Equal(t, http.StatusConflict, writer.Code)
+
+	_ = dbTest.FindById(context.Background(), user.ID, &user)
+	assert.Equal(t, "changeemailrace@test.com", user.Email)
+	assert.Empty(t, user.PendingEmail)
+}
+
+func TestUserAPI_ChangeEmailTakenByDeletedUser(t *testing.T) {
+	defer cleanData()
+
+	deleted := model.User{Email: "deletedowner@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &deleted)
+	dbTest.GetDB().Delete(&deleted)
+	user := model.User{Email: "changeemaildeleted@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	token, _ := sessionTokens(user.ID)
+
+	req := &dto.ChangeEmailReq{Email: deleted.Email, Password: "test123456"}
+	writer := makeRequest("POST", "/auth/change-email", req, token)
+	assert.Equal(t, http.StatusConflict, writer.Code)
+}
+
+func TestUserAPI_ChangeEmailWrongPassword(t *testing.T) {
+	req := &dto.ChangeEmailReq{Email: "other@test.com", Password: "wrong123456"}
+	writer := makeRequest("POST", "/auth/change-email", req, accessToken())
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+}
+
+// Reset Password
+// =================================================================================================
+
+func TestUserAPI_ForgotPasswordUnknownEmail(t *testing.T) {
+	req := &dto.ForgotPasswordReq{Email: "unknown@test.com"}
+	writer := makeRequest("POST", "/auth/forgot-password", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestUserAPI_ResetPasswordSuccess(t *testing.T) {
+	defer cleanData()
+
+	expiresAt := time.Now().Add(time.Hour)
+	user := model.User{
+		Email:               "resetpassword@test.com",
+		Password:            "test123456",
+		ResetTokenHash:      utils.HashToken("reset-token"),
+		ResetTokenExpiresAt: &expiresAt,
+	}
+	dbTest.Create(context.Background(), &user)
+	_, refresh := sessionTokens(user.ID)
+
+	req := &dto.ResetPasswordReq{Token: "reset-token", NewPassword: "new123456"}
+	writer := makeRequest("POST", "/auth/reset-password", req, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("POST", "/auth/refresh", nil, refresh)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("POST", "/auth/reset-password", req, "")
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+}
+
+func TestUserAPI_ResetPasswordExpiredToken(t *testing.T) {
+	defer cleanData()
+
+	expiresAt := time.Now().Add(-time.Minute)
+	dbTest.Create(context.Background(), &model.User{
+		Email:               "resetexpired@test.com",
+		Password:            "test123456",
+		ResetTokenHash:      utils.HashToken("expired-token"),
+		ResetTokenExpiresAt: &expiresAt,
+	})
+
+	req := &dto.ResetPasswordReq{Token: "expired-token", NewPassword: "new123456"}
+	writer := makeRequest("POST", "/auth/reset-password", req, "")
+	var response map[string]map[string]string
+	_ = json.Unmarshal(writer.Body.Bytes(), &response)
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+	assert.Equal(t, "Invalid or expired reset token", response["error"]["message"])
+}
+
+// Admin
+// =================================================================================================
+
+func TestUserAPI_AdminListUsersSuccess(t *testing.T) {
+	defer cleanData()
+
+	dbTest.Create(context.Background(), &model.User{Email: "adminlist@test.com", Password: "test123456"})
+
+	writer := makeRequest("GET", "/admin/users?email=adminlist&role=customer", nil, adminToken("admin-list"))
+	var res dto.ListUsersRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, 1, len(res.Users))
+	assert.Equal(t, "adminlist@test.com", res.Users[0].Email)
+	assert.Equal(t, int64(1), res.Pagination.Total)
+}
+
+func TestUserAPI_AdminListUsersForbidden(t *testing.T) {
+	writer := makeRequest("GET", "/admin/users", nil, accessToken())
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+}
+
+func TestUserAPI_AdminListUsersInvalidRole(t *testing.T) {
+	defer cleanData()
+
+	writer := makeRequest("GET", "/admin/users?role=owner", nil, adminToken("admin-list"))
+	assert.Equal(t, http.StatusInternalServerError, writer.Code)
+}
+
+func TestUserAPI_AdminUpdateRoleSuccess(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "adminrole@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	token, _ := sessionTokens(user.ID)
+
+	req := &dto.UpdateRoleReq{Role: "admin"}
+	writer := makeRequest("PUT", "/admin/users/"+user.ID+"/role", req, adminToken("admin-role"))
+	var res dto.UserDetail
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "admin", res.Role)
+
+	writer = makeRequest("GET", "/auth/me", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestUserAPI_AdminUpdateRoleUserNotFound(t *testing.T) {
+	defer cleanData()
+
+	req := &dto.UpdateRoleReq{Role: "admin"}
+	writer := makeRequest("PUT", "/admin/users/user-not-found/role", req, adminToken("admin-role"))
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+}
+
+func TestUserAPI_AdminApproveUserSuccess(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "adminapprove@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+
+	writer := makeRequest("PUT", "/admin/users/"+user.ID+"/approve", nil, adminToken("admin-approve"))
+	var res dto.UserDetail
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.True(t, res.Approve)
+}
+
+func TestUserAPI_AdminDisableUser(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "admindisable@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	access, refresh := sessionTokens(user.ID)
+	admin := adminToken("admin-disable")
+
+	writer := makeRequest("PUT", "/admin/users/"+user.ID+"/disabled", &dto.SetDisabledReq{Disabled: true}, admin)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("GET", "/auth/me", nil, access)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("POST", "/auth/refresh", nil, refresh)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	login := dto.LoginReq{Email: user.Email, Password: "test123456"}
+	writer = makeRequest("POST", "/auth/login", login, "")
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+
+	writer = makeRequest("PUT", "/admin/users/"+user.ID+"/disabled", &dto.SetDisabledReq{Disabled: false}, admin)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("POST", "/auth/login", login, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+// Audit Events
+// =================================================================================================
+
+func TestUserAPI_AuditEvents(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "audit@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+
+	login := dto.LoginReq{Email: user.Email, Password: "wrong123456"}
+	writer := makeRequest("POST", "/auth/login", login, "")
+	assert.NotEqual(t, http.StatusOK, writer.Code)
+
+	login.Password = "test123456"
+	writer = makeRequest("POST", "/auth/login", login, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	token := adminToken("admin-audit")
+	writer = makeRequest("GET", "/admin/audit-events?type=login&user_id="+user.ID, nil, token)
+	var res dto.ListAuditEventsRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, int64(2), res.Pagination.Total)
+	assert.Equal(t, "success", res.Events[0].Outcome)
+	assert.Equal(t, "failure", res.Events[1].Outcome)
+	assert.Equal(t, "wrong password", res.Events[1].Reason)
+	assert.Equal(t, "http", res.Events[0].Transport)
+	assert.Equal(t, user.Email, res.Events[0].Email)
+
+	writer = makeRequest("GET", "/admin/audit-events?outcome=failure&user_id="+user.ID+"&limit=1", nil, token)
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, int64(1), res.Pagination.Total)
+	assert.Equal(t, 1, len(res.Events))
+
+	writer = makeRequest("GET", "/admin/audit-events?outcome=unknown", nil, token)
+	assert.Equal(t, http.StatusInternalServerError, writer.Code)
+
+	writer = makeRequest("GET", "/admin/audit-events", nil, accessToken())
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+}
+
+func TestUserAPI_AuditEventsUnknownEmail(t *testing.T) {
+	defer cleanData()
+
+	login := dto.LoginReq{Email: "auditunknown@test.com", Password: "test123456"}
+	writer := makeRequest("POST", "/auth/login", login, "")
+	assert.NotEqual(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("GET", "/admin/audit-events?email=auditunknown", nil, adminToken("admin-audit"))
+	var res dto.ListAuditEventsRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, 1, len(res.Events))
+	assert.Equal(t, "", res.Events[0].UserID)
+	assert.Equal(t, "failure", res.Events[0].Outcome)
+}
+
+func TestUserAPI_AuditEventsPurge(t *testing.T) {
+	log := audit.New(dbTest)
+	log.Record(context.Background(), &audit.Event{Type: audit.EventLogout, UserID: "audit-purge"})
+
+	purged, err := log.Purge(context.Background(), time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, purged, int64(1))
+
+	events, _, err := log.List(context.Background(), &audit.Filter{UserID: "audit-purge"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(events))
+}
+
+// API Keys
+// =================================================================================================
+
+func TestUserAPI_APIKeys(t *testing.T) {
+	defer cleanData()
+
+	admin := model.User{Email: "apikeyadmin@test.com", Password: "test123456", Role: model.UserRoleAdmin}
+	dbTest.Create(context.Background(), &admin)
+	token := adminToken(admin.ID)
+
+	req := &dto.CreateAPIKeyReq{Name: "back-office", Scopes: []string{apikey.ScopeUsersRead}}
+	writer := makeRequest("POST", "/admin/api-keys", req, token)
+	var created dto.CreateAPIKeyRes
+	parseResponseResult(writer.Body.Bytes(), &created)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.True(t, strings.HasPrefix(created.Key, apikey.Prefix))
+	assert.Equal(t, admin.ID, created.APIKey.UserID)
+	assert.Equal(t, "admin", created.APIKey.Role)
+
+	var stored apikey.Key
+	assert.NoError(t, dbTest.FindById(context.Background(), created.APIKey.ID, &stored))
+	assert.Equal(t, utils.HashToken(created.Key), stored.Hash)
+	assert.Nil(t, stored.LastUsedAt)
+
+	writer = makeKeyRequest("GET", "/admin/users", nil, created.Key)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeKeyRequest("PUT", "/admin/users/"+admin.ID+"/approve", nil, created.Key)
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+
+	writer = makeKeyRequest("GET", "/auth/me", nil, created.Key)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeKeyRequest("GET", "/admin/api-keys", nil, created.Key)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("GET", "/admin/api-keys", nil, token)
+	var list dto.ListAPIKeysRes
+	parseResponseResult(writer.Body.Bytes(), &list)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	var listed *dto.APIKey
+	for _, key := range list.APIKeys {
+		if key.ID == created.APIKey.ID {
+			listed = key
+		}
+	}
+	assert.NotNil(t, listed)
+	assert.NotNil(t, listed.LastUsedAt)
+
+	writer = makeRequest("DELETE", "/admin/api-keys/"+created.APIKey.ID, nil, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeKeyRequest("GET", "/admin/users", nil, created.Key)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("DELETE", "/admin/api-keys/"+created.APIKey.ID, nil, token)
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+}
+
+func TestUserAPI_APIKeyExpired(t *testing.T) {
+	defer cleanData()
+
+	admin := model.User{Email: "apikeyexpired@test.com", Password: "test123456", Role: model.UserRoleAdmin}
+	dbTest.Create(context.Background(), &admin)
+
+	past := time.Now().Add(-time.Hour)
+	req := &dto.CreateAPIKeyReq{Name: "expired", Scopes: []string{apikey.ScopeUsersRead}, ExpiresAt: &past}
+	writer := makeRequest("POST", "/admin/api-keys", req, adminToken(admin.ID))
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+
+	key := &apikey.Key{UserID: admin.ID, Role: "admin", Scopes: []string{apikey.ScopeUsersRead}, ExpiresAt: &past}
+	raw, err := apikey.NewStore(dbTest).Create(context.Background(), key)
+	assert.NoError(t, err)
+
+	writer = makeKeyRequest("GET", "/admin/users", nil, raw)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestUserAPI_APIKeyFollowsUser(t *testing.T) {
+	defer cleanData()
+
+	admin := model.User{Email: "apikeyfollow@test.com", Password: "test123456", Role: model.UserRoleAdmin}
+	dbTest.Create(context.Background(), &admin)
+	token := adminToken("admin-follow")
+
+	req := &dto.CreateAPIKeyReq{Name: "follow", UserID: admin.ID, Scopes: []string{apikey.ScopeUsersRead}}
+	writer := makeRequest("POST", "/admin/api-keys", req, token)
+	var created dto.CreateAPIKeyRes
+	parseResponseResult(writer.Body.Bytes(), &created)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("PUT", "/admin/users/"+admin.ID+"/role", &dto.UpdateRoleReq{Role: "customer"}, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeKeyRequest("GET", "/admin/users", nil, created.Key)
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+
+	writer = makeRequest("PUT", "/admin/users/"+admin.ID+"/disabled", &dto.SetDisabledReq{Disabled: true}, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeKeyRequest("GET", "/admin/users", nil, created.Key)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+// Sessions
+// =================================================================================================
+
+func TestUserAPI_Sessions(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "sessions@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+
+	login := dto.LoginReq{Email: user.Email, Password: "test123456"}
+	var first, second dto.LoginRes
+	writer := makeRequest("POST", "/auth/login", login, "")
+	parseResponseResult(writer.Body.Bytes(), &first)
+	writer = makeRequest("POST", "/auth/login", login, "")
+	parseResponseResult(writer.Body.Bytes(), &second)
+
+	writer = makeRequest("GET", "/auth/sessions", nil, second.AccessToken)
+	var res dto.ListSessionsRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, 2, len(res.Sessions))
+
+	var other *dto.Session
+	for _, session := range res.Sessions {
+		assert.False(t, session.CreatedAt.IsZero())
+		assert.False(t, session.LastUsedAt.IsZero())
+		if !session.Current {
+			other = session
+		}
+	}
+	assert.NotNil(t, other)
+
+	writer = makeRequest("DELETE", "/auth/sessions/"+other.ID, nil, second.AccessToken)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("POST", "/auth/refresh", nil, first.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("GET", "/auth/me", nil, first.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+
+	writer = makeRequest("POST", "/auth/refresh", nil, second.RefreshToken)
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestUserAPI_RevokeSessionNotFound(t *testing.T) {
+	writer := makeRequest("DELETE", "/auth/sessions/session-not-found", nil, accessToken())
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+}
+
+// JWKS
+// =================================================================================================
+
+func TestUserAPI_JWKS(t *testing.T) {
+	writer := makeRequest("GET", "/.well-known/jwks.json", nil, "")
+	var response jtoken.JWKS
+	_ = json.Unmarshal(writer.Body.Bytes(), &response)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotNil(t, response.Keys)
+}
+
+// Two-Factor Authentication
+// =================================================================================================
+
+func enableTOTP(t *testing.T, userID string) (string, []string) {
+	token, _ := sessionTokens(userID)
+
+	writer := makeRequest("POST", "/auth/2fa/enroll", nil, token)
+	var enroll dto.EnrollTOTPRes
+	parseResponseResult(writer.Body.Bytes(), &enroll)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotEmpty(t, enroll.URI)
+
+	code, err := totp.Code(enroll.Secret, totp.Step(time.Now()))
+	assert.NoError(t, err)
+	writer = makeRequest("POST", "/auth/2fa/confirm", &dto.TOTPCodeReq{Code: code}, token)
+	var confirm dto.ConfirmTOTPRes
+	parseResponseResult(writer.Body.Bytes(), &confirm)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotEmpty(t, confirm.RecoveryCodes)
+
+	return enroll.Secret, confirm.RecoveryCodes
+}
+
+func TestUserAPI_LoginMFARecoveryCode(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "mfarecovery@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	_, recoveryCodes := enableTOTP(t, user.ID)
+
+	login := dto.LoginReq{Email: user.Email, Password: "test123456"}
+	writer := makeRequest("POST", "/auth/login", login, "")
+	var res dto.LoginRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.True(t, res.MFARequired)
+	assert.Empty(t, res.AccessToken)
+	assert.NotEmpty(t, res.MFAToken)
+
+	req := &dto.LoginMFAReq{MFAToken: res.MFAToken, Code: recoveryCodes[0]}
+	writer = makeRequest("POST", "/auth/login/mfa", req, "")
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotEmpty(t, res.AccessToken)
+	assert.NotEmpty(t, res.RefreshToken)
+
+	writer = makeRequest("POST", "/auth/login/mfa", req, "")
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestUserAPI_LoginMFAWrongCode(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "mfawrong@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	enableTOTP(t, user.ID)
+
+	login := dto.LoginReq{Email: user.Email, Password: "test123456"}
+	writer := makeRequest("POST", "/auth/login", login, "")
+	var res dto.LoginRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+
+	req := &dto.LoginMFAReq{MFAToken: res.MFAToken, Code: "000000"}
+	writer = makeRequest("POST", "/auth/login/mfa", req, "")
+	var response map[string]map[string]string
+	_ = json.Unmarshal(writer.Body.Bytes(), &response)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+	assert.Equal(t, "Invalid code", response["error"]["message"])
+}
+
+func TestUserAPI_LoginMFAInvalidToken(t *testing.T) {
+	req := &dto.LoginMFAReq{MFAToken: accessToken(), Code: "000000"}
+	writer := makeRequest("POST", "/auth/login/mfa", req, "")
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestUserAPI_DisableTOTP(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "mfadisable@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	_, recoveryCodes := enableTOTP(t, user.ID)
+	token, _ := sessionTokens(user.ID)
+
+	writer := makeRequest("POST", "/auth/2fa/disable", &dto.TOTPCodeReq{Code: recoveryCodes[1]}, token)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	login := dto.LoginReq{Email: user.Email, Password: "test123456"}
+	writer = makeRequest("POST", "/auth/login", login, "")
+	var res dto.LoginRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.False(t, res.MFARequired)
+	assert.NotEmpty(t, res.AccessToken)
+}
+
+// OIDC
+// =================================================================================================
+
+func TestUserAPI_OIDCLoginNewUser(t *testing.T) {
+	defer cleanData()
+
+	testIdP.SetUser(oidctest.User{Subject: "oidc-new", Email: "oidcnew@test.com", EmailVerified: true, Name: "OIDC New"})
+	writer := makeRequest("GET", oidcCallback(), nil, "")
+	var res dto.LoginRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotEmpty(t, res.AccessToken)
+	assert.NotEmpty(t, res.RefreshToken)
+
+	user, err := repository.NewUserRepository(dbTest).GetUserByEmail(context.Background(), "oidcnew@test.com")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, res.User.ID)
+	assert.True(t, user.Approve)
+	assert.Equal(t, "OIDC New", user.DisplayName)
+
+	writer = makeRequest("GET", "/auth/me", nil, res.AccessToken)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("GET", "/admin/audit-events?type=login_oidc&user_id="+user.ID, nil, adminToken("admin-oidc"))
+	var events dto.ListAuditEventsRes
+	parseResponseResult(writer.Body.Bytes(), &events)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, int64(1), events.Pagination.Total)
+}
+
+func TestUserAPI_OIDCLoginLinksUser(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "oidclink@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+
+	testIdP.SetUser(oidctest.User{Subject: "oidc-link", Email: user.Email, EmailVerified: true})
+	writer := makeRequest("GET", oidcCallback(), nil, "")
+	var res dto.LoginRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, user.ID, res.User.ID)
+
+	linked, err := repository.NewUserRepository(dbTest).GetUserByID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.True(t, linked.Approve)
+}
+
+func TestUserAPI_OIDCLoginUnverifiedEmail(t *testing.T) {
+	defer cleanData()
+
+	testIdP.SetUser(oidctest.User{Subject: "oidc-unverified", Email: "oidcunverified@test.com"})
+	writer := makeRequest("GET", oidcCallback(), nil, "")
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+
+	exists, err := repository.NewUserRepository(dbTest).EmailExists(context.Background(), "oidcunverified@test.com")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestUserAPI_OIDCCallbackReplay(t *testing.T) {
+	defer cleanData()
+
+	testIdP.SetUser(oidctest.User{Subject: "oidc-replay", Email: "oidcreplay@test.com", EmailVerified: true})
+	callback := oidcCallback()
+	writer := makeRequest("GET", callback, nil, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	writer = makeRequest("GET", callback, nil, "")
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+
+	writer = makeRequest("GET", "/auth/oidc/callback?error=access_denied", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+// Impersonation
+// =================================================================================================
+
+func TestUserAPI_Impersonate(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "impersonated@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	admin := adminToken("admin-impersonate")
+
+	req := &dto.ImpersonateReq{Reason: "ticket 42"}
+	writer := makeRequest("POST", "/admin/users/"+user.ID+"/impersonate", req, admin)
+	var res dto.ImpersonateRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, user.ID, res.User.ID)
+	assert.False(t, res.ReadOnly)
+
+	claims, err := jtoken.ValidateToken(res.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, claims.Subject)
+	assert.Equal(t, "admin-impersonate", claims.Actor.Subject)
+
+	writer = makeRequest("GET", "/auth/me", nil, res.AccessToken)
+	var me dto.User
+	parseResponseResult(writer.Body.Bytes(), &me)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, user.Email, me.Email)
+
+	writer = makeRequest("GET", "/admin/audit-events?actor_id=admin-impersonate", nil, admin)
+	var events dto.ListAuditEventsRes
+	parseResponseResult(writer.Body.Bytes(), &events)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, int64(2), events.Pagination.Total)
+	assert.Equal(t, "impersonated_request", events.Events[0].Type)
+	assert.Equal(t, "GET /auth/me", events.Events[0].Resource)
+	assert.Equal(t, user.ID, events.Events[0].UserID)
+	assert.Equal(t, "impersonation_start", events.Events[1].Type)
+	assert.Equal(t, "ticket 42", events.Events[1].Reason)
+
+	// The token ends with the admin's session.
+	writer = makeRequest("POST", "/auth/logout", nil, admin)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	writer = makeRequest("GET", "/auth/me", nil, res.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestUserAPI_ImpersonateLogout(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "impersonatedlogout@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	admin := adminToken("admin-impersonate-logout")
+
+	writer := makeRequest("POST", "/admin/users/"+user.ID+"/impersonate", &dto.ImpersonateReq{}, admin)
+	var res dto.ImpersonateRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	// Logging out ends the admin's session the token lives in.
+	writer = makeRequest("POST", "/auth/logout", nil, res.AccessToken)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	writer = makeRequest("GET", "/auth/me", nil, res.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+	writer = makeRequest("GET", "/auth/me", nil, admin)
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
+
+func TestUserAPI_ImpersonateReadOnly(t *testing.T) {
+	defer cleanData()
+
+	user := model.User{Email: "impersonatedro@test.com", Password: "test123456"}
+	dbTest.Create(context.Background(), &user)
+	admin := adminToken("admin-impersonate-ro")
+
+	req := &dto.ImpersonateReq{ReadOnly: true}
+	writer := makeRequest("POST", "/admin/users/"+user.ID+"/impersonate", req, admin)
+	var res dto.ImpersonateRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.True(t, res.ReadOnly)
+
+	writer = makeRequest("GET", "/auth/me", nil, res.AccessToken)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	displayName := "Changed"
+	writer = makeRequest("PATCH", "/auth/me", &dto.UpdateProfileReq{DisplayName: &displayName}, res.AccessToken)
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+
+	writer = makeRequest("GET", "/admin/audit-events?actor_id=admin-impersonate-ro&outcome=failure", nil, admin)
+	var events dto.ListAuditEventsRes
+	parseResponseResult(writer.Body.Bytes(), &events)
+	assert.Equal(t, int64(1), events.Pagination.Total)
+	assert.Equal(t, "PATCH /auth/me", events.Events[0].Resource)
+}
+
+func TestUserAPI_ImpersonateForbidden(t *testing.T) {
+	defer cleanData()
+
+	other := model.User{Email: "impersonatedadmin@test.com", Password: "test123456", Role: model.UserRoleAdmin}
+	dbTest.Create(context.Background(), &other)
+
+	writer := makeRequest("POST", "/admin/users/"+other.ID+"/impersonate", &dto.ImpersonateReq{}, adminToken("admin-impersonate"))
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+
+	writer = makeRequest("POST", "/admin/users/unknown/impersonate", &dto.ImpersonateReq{}, adminToken("admin-impersonate"))
+	assert.Equal(t, http.StatusNotFound, writer.Code)
+
+	writer = makeRequest("POST", "/admin/users/"+other.ID+"/impersonate", &dto.ImpersonateReq{}, accessToken())
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+}
+
+// Cookie auth
+// =================================================================================================
+
+func TestUserAPI_CookieAuth(t *testing.T) {
+	defer cleanData()
+
+	cfg := config.GetConfig()
+	cfg.AuthCookies = true
+	defer func() { cfg.AuthCookies = false }()
+
+	login := dto.LoginReq{Email: "test@test.com", Password: "test123456"}
+	writer := makeRequest("POST", "/auth/login", login, "")
+	var res dto.LoginRes
+	parseResponseResult(writer.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotEmpty(t, res.AccessToken)
+	assert.Empty(t, res.RefreshToken)
+
+	cookies := writer.Result().Cookies()
+	var csrf string
+	for _, cookie := range cookies {
+		if cookie.Name == middleware.CSRFCookie {
+			csrf = cookie.Value
+		}
+	}
+	assert.NotEmpty(t, csrf)
+
+	cookieRequest := func(method, url, csrfToken string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(method, url, nil)
+		for _, cookie := range cookies {
+			request.AddCookie(cookie)
+		}
+		if csrfToken != "" {
+			request.Header.Set(middleware.CSRFHeader, csrfToken)
+		}
+		writer := httptest.NewRecorder()
+		testRouter.ServeHTTP(writer, request)
+		return writer
+	}
+
+	writer = cookieRequest("POST", "/auth/refresh", "")
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+
+	writer = cookieRequest("POST", "/auth/refresh", csrf)
+	var refreshed dto.RefreshTokenRes
+	parseResponseResult(writer.Body.Bytes(), &refreshed)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotEmpty(t, refreshed.AccessToken)
+	assert.Empty(t, refreshed.RefreshToken)
+	assert.NotEmpty(t, writer.Result().Cookies())
+}